@@ -1,17 +1,24 @@
 package bitso
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"strconv"
+	"time"
 )
 
 // Account allows you to access to the Bitso API
 type Account struct {
-	keys *Keys
+	// NonceSource provides the nonces of the requests. Leaving it
+	// nil uses a MonotonicNonce shared by the whole process.
+	NonceSource NonceSource
+	// Signer signs the requests. Leaving it nil signs
+	// them in memory with the Secret of the Keys.
+	Signer Signer
+
+	keys   *Keys
+	client *Client
 }
 
 // Keys stores the information needed to access
@@ -24,33 +31,72 @@ type Keys struct {
 
 type openOrders struct {
 	fields
-	Book string `json:"book,omitempty"`
+	Book Book `json:"book,omitempty"`
 }
 
+// Order is an order placed in a book. With the v2 API Type is
+// "0" for buy and "1" for sell; with the v3 API Type is the
+// order type (limit or market) and Side holds buy or sell.
 type Order struct {
 	fields
-	Id       string `json:"id,omitempty"`
-	Type     string `json:"type,omitempty"`
-	Price    string `json:"price,omitempty"`
-	Amount   string `json:"amount,omitempty"`
-	Datetime string `json:"datetime,omitempty"`
-	Status   string `json:"status,omitempty"`
-	Book     string `json:"book,omitempty"`
+	Id             string    `json:"id,omitempty"`
+	Type           string    `json:"type,omitempty"`
+	Side           string    `json:"side,omitempty"`
+	Price          Decimal   `json:"price"`
+	Amount         Decimal   `json:"amount"`
+	OriginalAmount Decimal   `json:"original_amount"`
+	UnfilledAmount Decimal   `json:"unfilled_amount"`
+	Datetime       time.Time `json:"datetime"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Status         string    `json:"status,omitempty"`
+	Book           Book      `json:"book,omitempty"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	v := &struct {
+		*order
+		Datetime  timestamp `json:"datetime"`
+		UpdatedAt timestamp `json:"updated_at"`
+	}{order: (*order)(o)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	o.Datetime = v.Datetime.Time()
+	o.UpdatedAt = v.UpdatedAt.Time()
+	return nil
 }
 
 type request struct {
 	fields
 }
 
+// orderRequest identifies an order in the v2 requests.
+type orderRequest struct {
+	fields
+	Id string `json:"id"`
+}
+
 type Balance struct {
 	fields
-	Fee          string `json:"fee,omitempty"`
-	MXNBalance   string `json:"mxn_balance,omitempty"`
-	BTCBalance   string `json:"btc_balance,omitempty"`
-	MXNReserved  string `json:"mxn_reserved,omitempty"`
-	BTCReserved  string `json:"btc_reserved,omitempty"`
-	MXNAvailable string `json:"mxn_available,omitempty"`
-	BTCAvailable string `json:"btc_available,omitempty"`
+	Fee          Decimal `json:"fee"`
+	MXNBalance   Decimal `json:"mxn_balance"`
+	BTCBalance   Decimal `json:"btc_balance"`
+	MXNReserved  Decimal `json:"mxn_reserved"`
+	BTCReserved  Decimal `json:"btc_reserved"`
+	MXNAvailable Decimal `json:"mxn_available"`
+	BTCAvailable Decimal `json:"btc_available"`
+	// Balances holds every currency, it's only set by the v3 API.
+	Balances []*CurrencyBalance `json:"balances,omitempty"`
+}
+
+// CurrencyBalance is the balance of a single currency.
+type CurrencyBalance struct {
+	Currency  Currency `json:"currency"`
+	Total     Decimal  `json:"total"`
+	Locked    Decimal  `json:"locked"`
+	Available Decimal  `json:"available"`
 }
 
 // fields is included in every request made to private endpoints
@@ -70,12 +116,13 @@ func (a *fields) setAuthentication(key, signature string, nonce int64) {
 func (a *fields) getError() error {
 	e := a.Error
 	if e.Message != "" {
-		err := errors.New(fmt.Sprintf("%s (code: %v)", e.Message, e.Code))
-		return err
+		return &APIError{Code: strconv.Itoa(e.Code), Message: e.Message, Status: e.Status}
 	}
 	return nil
 }
 
+// Error is the error of the v2 responses, they're returned as
+// *APIError, which errors.As also converts to *Error.
 type Error struct {
 	Code    int    `json:"error,omitempty"`
 	Message string `json:"message,omitempty"`
@@ -92,47 +139,98 @@ type requestBody interface {
 }
 
 // Authenticate receives a Keys used to
-// authenticate into the private endpoints.
+// authenticate into the private endpoints
+// using the DefaultClient.
 func Authenticate(keys *Keys) *Account {
-	return &Account{keys}
+	return DefaultClient.Authenticate(keys)
 }
 
 func (c *Account) Balance() (*Balance, error) {
+	return c.BalanceContext(context.Background())
+}
+
+// BalanceContext is like Balance with a context.
+func (c *Account) BalanceContext(ctx context.Context) (*Balance, error) {
+	if c.client.Version == V3 {
+		return c.balanceV3(ctx)
+	}
 	balance := &Balance{}
-	if err := c.post(balancePath, balance); err != nil {
+	if err := c.post(ctx, balancePath, &request{}, balance); err != nil {
 		return nil, err
 	}
 	return balance, nil
 }
 
 func (c *Account) OpenOrders() ([]*Order, error) {
+	return c.OpenOrdersContext(context.Background())
+}
+
+// OpenOrdersContext is like OpenOrders with a context.
+func (c *Account) OpenOrdersContext(ctx context.Context) ([]*Order, error) {
+	return c.openOrdersIn(ctx, "")
+}
+
+// openOrdersIn returns the open orders in book. An empty book
+// uses the default of the API.
+func (c *Account) openOrdersIn(ctx context.Context, book Book) ([]*Order, error) {
+	if c.client.Version == V3 {
+		return c.openOrdersV3(ctx, book)
+	}
 	var orders []*Order
-	openOrders := &openOrders{}
-	if err := c.post(openOrdersPath, openOrders, &orders); err != nil {
+	openOrders := &openOrders{Book: book}
+	if err := c.post(ctx, openOrdersPath, openOrders, &orders); err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
 func (c *Account) LookupOrder(id string) ([]*Order, error) {
+	return c.LookupOrderContext(context.Background(), id)
+}
+
+// LookupOrderContext is like LookupOrder with a context.
+func (c *Account) LookupOrderContext(ctx context.Context, id string) ([]*Order, error) {
+	if c.client.Version == V3 {
+		return c.lookupOrderV3(ctx, id)
+	}
 	var orders []*Order
-	order := &Order{Id: id}
-	if err := c.post(lookupOrderPath, order, &orders); err != nil {
+	order := &orderRequest{Id: id}
+	if err := c.post(ctx, lookupOrderPath, order, &orders); err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
-func (c *Account) getSignature(nonce int64) string {
+func (c *Account) nonce() (int64, error) {
+	if c.NonceSource == nil {
+		return defaultNonceSource.Nonce()
+	}
+	return c.NonceSource.Nonce()
+}
+
+func (c *Account) signer() Signer {
 	if c.validateKeys() == false {
 		panic("can't generate a signature without keys")
 	}
+	if c.Signer == nil {
+		return NewHMACSigner(c.keys.Secret)
+	}
+	return c.Signer
+}
+
+func (c *Account) getSignature(ctx context.Context, nonce int64) (string, error) {
+	signer := c.signer()
 	key := c.keys.Key
 	clientId := c.keys.ClientId
-	secret := c.keys.Secret
 	message := fmt.Sprintf("%v%v%v", nonce, key, clientId)
-	signature := sign(message, secret)
-	return signature
+	return signer.Sign(ctx, []byte(message))
+}
+
+// getSignatureV3 signs the nonce, HTTP method, request path
+// and payload as required by the v3 API.
+func (c *Account) getSignatureV3(ctx context.Context, nonce int64, method, requestPath string, payload []byte) (string, error) {
+	message := fmt.Sprintf("%v%s%s%s", nonce, method, requestPath, payload)
+	return c.signer().Sign(ctx, []byte(message))
 }
 
 func (c *Account) validateKeys() bool {
@@ -142,7 +240,17 @@ func (c *Account) validateKeys() bool {
 	return true
 }
 
-func (c *Account) post(path string, schemas ...interface{}) error {
+// readOnlyPaths are the v2 private endpoints that don't change
+// the account, they are retried like the public ones.
+var readOnlyPaths = map[string]bool{
+	balancePath:               true,
+	openOrdersPath:            true,
+	lookupOrderPath:           true,
+	userTransactionsPath:      true,
+	bitcoinDepositAddressPath: true,
+}
+
+func (c *Account) post(ctx context.Context, path string, schemas ...interface{}) error {
 	var respSchema interface{}
 	reqSchema := schemas[0].(requestBody)
 	if len(schemas) == 2 {
@@ -150,22 +258,27 @@ func (c *Account) post(path string, schemas ...interface{}) error {
 	} else {
 		respSchema = reqSchema
 	}
-	nonce := getNonce()
-	signature := c.getSignature(nonce)
-	reqSchema.setAuthentication(c.keys.Key, signature, nonce)
-	payload, err := json.Marshal(reqSchema)
-	if err != nil {
-		return err
-	}
-	buff := bytes.NewBuffer(payload)
-	resp, err := http.Post(URL+path, "application/json", buff)
-	if err != nil {
+	// Every attempt is signed with a new nonce.
+	var body []byte
+	err := c.client.retry(ctx, readOnlyPaths[path], func() error {
+		nonce, err := c.nonce()
+		if err != nil {
+			return err
+		}
+		signature, err := c.getSignature(ctx, nonce)
+		if err != nil {
+			return err
+		}
+		reqSchema.setAuthentication(c.keys.Key, signature, nonce)
+		payload, err := json.Marshal(reqSchema)
+		if err != nil {
+			return err
+		}
+		body, err = c.client.post(ctx, path, payload)
 		return err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	})
 	if err != nil {
-		return err
+		return withResponse(err, path, body)
 	}
 	err = json.Unmarshal(body, respSchema)
 	if err != nil {
@@ -173,9 +286,10 @@ func (c *Account) post(path string, schemas ...interface{}) error {
 		if err = json.Unmarshal(body, f); err != nil {
 			return err
 		}
-		if err = f.getError(); err != nil {
-			return err
-		}
+		return withResponse(f.getError(), path, body)
+	}
+	if r, ok := respSchema.(requestBody); ok {
+		return withResponse(r.getError(), path, body)
 	}
 	return nil
 }
@@ -1,10 +1,12 @@
 package bitso
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 	. "github.com/smartystreets/goconvey/convey"
@@ -24,14 +26,16 @@ func TestAccount(t *testing.T) {
 		account := Authenticate(keys)
 
 		Convey("When the signature is generated", func() {
-			signature := account.getSignature(getNonce())
+			signature, err := account.getSignature(context.Background(), 1)
+			So(err, ShouldBeNil)
 
 			Convey("The signature should NOT be empty", func() {
 				So(signature, ShouldNotBeEmpty)
 			})
 
 			Convey("When a new signature is generated with a new nonce", func() {
-				newSignature := account.getSignature(getNonce())
+				newSignature, err := account.getSignature(context.Background(), 2)
+				So(err, ShouldBeNil)
 
 				Convey("The newSignature, should be different", func() {
 					So(newSignature, ShouldNotEqual, signature)
@@ -47,7 +51,7 @@ func TestAccount(t *testing.T) {
 			})
 
 			Convey("Fee should be 1.0000", func() {
-				So(balance.Fee, ShouldEqual, "1.0000")
+				So(balance.Fee.String(), ShouldEqual, "1.0000")
 			})
 		})
 
@@ -63,7 +67,7 @@ func TestAccount(t *testing.T) {
 
 		Convey("When a request for the balance is made", func() {
 			balance := &Balance{}
-			err := account.post(balancePath, balance)
+			err := account.post(context.Background(), balancePath, balance)
 
 			Convey("err should be 'Invalid API Code or Invalid Signature:  (code: 101)'", func() {
 				So(err.Error(), ShouldEqual, "Invalid API Code or Invalid Signature:  (code: 101)")
@@ -73,7 +77,7 @@ func TestAccount(t *testing.T) {
 		Convey("When a request for the open orders is made", func() {
 			var orders []Order
 			openOrders := &openOrders{}
-			err := account.post(openOrdersPath, openOrders, &orders)
+			err := account.post(context.Background(), openOrdersPath, openOrders, &orders)
 
 			Convey("err should be 'Invalid API Code or Invalid Signature:  (code: 101)'", func() {
 				So(err.Error(), ShouldEqual, "Invalid API Code or Invalid Signature:  (code: 101)")
@@ -103,32 +107,34 @@ func TestAccount(t *testing.T) {
 }
 
 func registerResponder() {
+	registerBooksResponder()
+
 	httpmock.RegisterResponder("GET", URL+tickerPath,
 		func(req *http.Request) (*http.Response, error) {
 			var ticker *TickerInfo
 			v := req.URL.Query()
-			book := v.Get("book")
+			book := Book(v.Get("book"))
 			if book == ETHMXN {
 				ticker = &TickerInfo{
-					High:      "213.97",
-					Last:      "212.30",
-					Timestamp: "1468809252",
-					Volume:    "149.25704647",
-					Vwap:      "210.00557165",
-					Low:       "205.92",
-					Ask:       "212.30",
-					Bid:       "208.27",
+					High:      MustParseDecimal("213.97"),
+					Last:      MustParseDecimal("212.30"),
+					Timestamp: time.Unix(1468809252, 0),
+					Volume:    MustParseDecimal("149.25704647"),
+					Vwap:      MustParseDecimal("210.00557165"),
+					Low:       MustParseDecimal("205.92"),
+					Ask:       MustParseDecimal("212.30"),
+					Bid:       MustParseDecimal("208.27"),
 				}
 			} else if book == BTCMXN || book == "" {
 				ticker = &TickerInfo{
-					High:      "12700.00",
-					Last:      "12640.00",
-					Timestamp: "1468809239",
-					Volume:    "84.97899364",
-					Vwap:      "12505.15042596",
-					Low:       "12388.17",
-					Ask:       "12640.00",
-					Bid:       "12554.88",
+					High:      MustParseDecimal("12700.00"),
+					Last:      MustParseDecimal("12640.00"),
+					Timestamp: time.Unix(1468809239, 0),
+					Volume:    MustParseDecimal("84.97899364"),
+					Vwap:      MustParseDecimal("12505.15042596"),
+					Low:       MustParseDecimal("12388.17"),
+					Ask:       MustParseDecimal("12640.00"),
+					Bid:       MustParseDecimal("12554.88"),
 				}
 			}
 			resp, err := httpmock.NewJsonResponse(200, ticker)
@@ -143,51 +149,27 @@ func registerResponder() {
 		func(req *http.Request) (*http.Response, error) {
 			var orderBook *OrderBookInfo
 			v := req.URL.Query()
-			book := v.Get("book")
+			book := Book(v.Get("book"))
+			if group := v.Get("group"); group != "0" && group != "1" {
+				return httpmock.NewStringResponse(400, "Invalid group value"), nil
+			}
 			if book == ETHMXN {
 				orderBook = &OrderBookInfo{
-					Bids: [][]string{
-						[]string{
-							"10720.00",
-							"3.15298000",
-						},
-						[]string{
-							"10712.40",
-							"0.00326724",
-						},
-						[]string{
-							"10711.69",
-							"0.17947681",
-						},
-						[]string{
-							"10709.96",
-							"1.12340008",
-						},
+					Bids: []*PriceLevel{
+						{Price: MustParseDecimal("10720.00"), Amount: MustParseDecimal("3.15298000")},
+						{Price: MustParseDecimal("10712.40"), Amount: MustParseDecimal("0.00326724")},
+						{Price: MustParseDecimal("10711.69"), Amount: MustParseDecimal("0.17947681")},
+						{Price: MustParseDecimal("10709.96"), Amount: MustParseDecimal("1.12340008")},
 					},
 				}
 			} else if book == BTCMXN || book == "" {
 				orderBook = &OrderBookInfo{
-					Bids: [][]string{
-						[]string{
-							"210.02",
-							"2.07146938",
-						},
-						[]string{
-							"206.62",
-							"50.00000000",
-						},
-						[]string{
-							"204.01",
-							"50.00000000",
-						},
-						[]string{
-							"204.00",
-							"6.11132353",
-						},
-						[]string{
-							"203.20",
-							"10.20000000",
-						},
+					Bids: []*PriceLevel{
+						{Price: MustParseDecimal("210.02"), Amount: MustParseDecimal("2.07146938")},
+						{Price: MustParseDecimal("206.62"), Amount: MustParseDecimal("50.00000000")},
+						{Price: MustParseDecimal("204.01"), Amount: MustParseDecimal("50.00000000")},
+						{Price: MustParseDecimal("204.00"), Amount: MustParseDecimal("6.11132353")},
+						{Price: MustParseDecimal("203.20"), Amount: MustParseDecimal("10.20000000")},
 					},
 				}
 			}
@@ -203,61 +185,61 @@ func registerResponder() {
 		func(req *http.Request) (*http.Response, error) {
 			var transactions []*Transaction
 			v := req.URL.Query()
-			book := v.Get("book")
-			time := v.Get("time")
+			book := Book(v.Get("book"))
+			frame := v.Get("time")
 			_ = v.Get("time")
 			if book == ETHMXN {
 				transactions = []*Transaction{
 					&Transaction{
-						Amount: "1.94511553",
-						Date:   "1470876646",
-						Price:  "212.03",
+						Amount: MustParseDecimal("1.94511553"),
+						Date:   time.Unix(1470876646, 0),
+						Price:  MustParseDecimal("212.03"),
 						Tid:    159075,
 						Side:   "sell",
 					},
 					&Transaction{
-						Amount: "1.79120536",
-						Date:   "1470876493",
-						Price:  "224.00",
+						Amount: MustParseDecimal("1.79120536"),
+						Date:   time.Unix(1470876493, 0),
+						Price:  MustParseDecimal("224.00"),
 						Tid:    159074,
 						Side:   "sell",
 					},
 				}
-				if time == "minute" {
+				if frame == "minute" {
 					transactions = transactions[0:1]
 				}
 			} else if book == BTCMXN || book == "" {
 				transactions = []*Transaction{
 					&Transaction{
-						Amount: "0.02200000",
-						Date:   "1470876646",
-						Price:  "10931.02",
+						Amount: MustParseDecimal("0.02200000"),
+						Date:   time.Unix(1470876646, 0),
+						Price:  MustParseDecimal("10931.02"),
 						Tid:    159075,
 						Side:   "sell",
 					},
 					&Transaction{
-						Amount: "0.14089557",
-						Date:   "1470876493",
-						Price:  "10931.02",
+						Amount: MustParseDecimal("0.14089557"),
+						Date:   time.Unix(1470876493, 0),
+						Price:  MustParseDecimal("10931.02"),
 						Tid:    159074,
 						Side:   "sell",
 					},
 					&Transaction{
-						Amount: "0.03561408",
-						Date:   "1470876493",
-						Price:  "10925.67",
+						Amount: MustParseDecimal("0.03561408"),
+						Date:   time.Unix(1470876493, 0),
+						Price:  MustParseDecimal("10925.67"),
 						Tid:    159073,
 						Side:   "sell",
 					},
 					&Transaction{
-						Amount: "0.01737102",
-						Date:   "1470876189",
-						Price:  "10925.67",
+						Amount: MustParseDecimal("0.01737102"),
+						Date:   time.Unix(1470876189, 0),
+						Price:  MustParseDecimal("10925.67"),
 						Tid:    159072,
 						Side:   "sell",
 					},
 				}
-				if time == "minute" {
+				if frame == "minute" {
 					transactions = transactions[0:2]
 				}
 			}
@@ -271,13 +253,13 @@ func registerResponder() {
 	httpmock.RegisterResponder("POST", URL+balancePath,
 		func(req *http.Request) (*http.Response, error) {
 			balance := &Balance{
-				Fee:          "1.0000",
-				BTCAvailable: "46.67902107",
-				MXNAvailable: "26864.57",
-				BTCBalance:   "46.67902107",
-				MXNBalance:   "26864.57",
-				BTCReserved:  "0.00000000",
-				MXNReserved:  "0.00",
+				Fee:          MustParseDecimal("1.0000"),
+				BTCAvailable: MustParseDecimal("46.67902107"),
+				MXNAvailable: MustParseDecimal("26864.57"),
+				BTCBalance:   MustParseDecimal("46.67902107"),
+				MXNBalance:   MustParseDecimal("26864.57"),
+				BTCReserved:  MustParseDecimal("0.00000000"),
+				MXNReserved:  MustParseDecimal("0.00"),
 			}
 			r := req.Body
 			body, err := ioutil.ReadAll(r)
@@ -308,25 +290,25 @@ func registerResponder() {
 			openOrders := &openOrders{}
 			orders := []*Order{
 				&Order{
-					Amount:   "0.01000000",
-					Datetime: "2015-11-12 12:37:01",
-					Price:    "5600.00",
+					Amount:   MustParseDecimal("0.01000000"),
+					Datetime: time.Date(2015, 11, 12, 12, 37, 1, 0, time.UTC),
+					Price:    MustParseDecimal("5600.00"),
 					Id:       "543cr2v32a1h684430tvcqx1b0vkr93wd694957cg8umhyrlzkgbaedmf976ia3v",
 					Type:     "1",
 					Status:   "1",
 				},
 				&Order{
-					Amount:   "0.12680000",
-					Datetime: "2015-11-12 12:33:47",
-					Price:    "4000.00",
+					Amount:   MustParseDecimal("0.12680000"),
+					Datetime: time.Date(2015, 11, 12, 12, 33, 47, 0, time.UTC),
+					Price:    MustParseDecimal("4000.00"),
 					Id:       "qlbga6b600n3xta7actori10z19acfb20njbtuhtu5xry7z8jswbaycazlkc0wf1",
 					Type:     "0",
 					Status:   "0",
 				},
 				&Order{
-					Amount:   "1.12560000",
-					Datetime: "2015-11-12 12:33:23",
-					Price:    "6123.55",
+					Amount:   MustParseDecimal("1.12560000"),
+					Datetime: time.Date(2015, 11, 12, 12, 33, 23, 0, time.UTC),
+					Price:    MustParseDecimal("6123.55"),
 					Id:       "d71e3xy2lowndkfmde6bwkdsvw62my6058e95cbr08eesu0687i5swyot4rf2yf8",
 					Type:     "1",
 					Status:   "0",
@@ -0,0 +1,190 @@
+package bitso
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Charset  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32HRP      = "bc"
+	bech32Const    = 1
+	bech32mConst   = 0x2bc830a3
+	// P2PKH and P2SH are the version bytes of mainnet
+	// base58 addresses.
+	versionP2PKH = 0x00
+	versionP2SH  = 0x05
+)
+
+var (
+	errInvalidBTCAddress = errors.New("Invalid BTC address")
+	errInvalidETHAddress = errors.New("Invalid ETH address")
+	errAddressChecksum   = errors.New("Invalid address checksum")
+)
+
+// ValidateBTCAddress checks that address is a mainnet base58 (P2PKH or
+// P2SH) or bech32/bech32m (segwit) address with a valid checksum.
+func ValidateBTCAddress(address string) error {
+	if strings.HasPrefix(strings.ToLower(address), bech32HRP+"1") {
+		return validateBech32(address)
+	}
+	return validateBase58(address)
+}
+
+func validateBase58(address string) error {
+	decoded, err := base58Decode(address)
+	if err != nil || len(decoded) != 25 {
+		return errInvalidBTCAddress
+	}
+	if decoded[0] != versionP2PKH && decoded[0] != versionP2SH {
+		return errInvalidBTCAddress
+	}
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[21:]) {
+		return errAddressChecksum
+	}
+	return nil
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return nil, errInvalidBTCAddress
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	decoded := n.Bytes()
+	// Every leading 1 is a leading zero byte.
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), decoded...), nil
+}
+
+func validateBech32(address string) error {
+	if len(address) > 90 || (strings.ToLower(address) != address && strings.ToUpper(address) != address) {
+		return errInvalidBTCAddress
+	}
+	address = strings.ToLower(address)
+	sep := strings.LastIndexByte(address, '1')
+	if address[:sep] != bech32HRP || len(address)-sep-1 < 7 {
+		return errInvalidBTCAddress
+	}
+	data := make([]byte, 0, len(address)-sep-1)
+	for _, r := range address[sep+1:] {
+		i := strings.IndexRune(bech32Charset, r)
+		if i < 0 {
+			return errInvalidBTCAddress
+		}
+		data = append(data, byte(i))
+	}
+	checksum := bech32Polymod(append(bech32ExpandHRP(bech32HRP), data...))
+	version := data[0]
+	program, ok := convertBits(data[1:len(data)-6], 5, 8)
+	if !ok || version > 16 || len(program) < 2 || len(program) > 40 {
+		return errInvalidBTCAddress
+	}
+	switch {
+	case version == 0 && len(program) != 20 && len(program) != 32:
+		return errInvalidBTCAddress
+	case version == 0 && checksum != bech32Const:
+		return errAddressChecksum
+	case version > 0 && checksum != bech32mConst:
+		return errAddressChecksum
+	}
+	return nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// convertBits regroups data from groups of from bits into
+// groups of to bits, rejecting non zero padding.
+func convertBits(data []byte, from, to uint) ([]byte, bool) {
+	acc, bits := uint(0), uint(0)
+	maxv := uint(1)<<to - 1
+	var converted []byte
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			converted = append(converted, byte(acc>>bits&maxv))
+		}
+	}
+	if bits >= from || (acc<<(to-bits))&maxv != 0 {
+		return nil, false
+	}
+	return converted, true
+}
+
+// ValidateETHAddress checks that address is a 0x prefixed hex address.
+// Mixed case addresses must match their EIP-55 checksum.
+func ValidateETHAddress(address string) error {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return errInvalidETHAddress
+	}
+	hexAddress := address[2:]
+	if _, err := hex.DecodeString(hexAddress); err != nil {
+		return errInvalidETHAddress
+	}
+	lower := strings.ToLower(hexAddress)
+	if hexAddress == lower || hexAddress == strings.ToUpper(hexAddress) {
+		return nil
+	}
+	if hexAddress != eip55(lower) {
+		return errAddressChecksum
+	}
+	return nil
+}
+
+// eip55 returns the checksummed form of a lower case hex address.
+func eip55(lower string) string {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hex.EncodeToString(hash.Sum(nil))
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c >= 'a' && c <= 'f' && digest[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return string(checksummed)
+}
@@ -0,0 +1,111 @@
+package bitso
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateBTCAddress(t *testing.T) {
+	Convey("Given a BTC address to validate", t, func() {
+		Convey("When it's a P2PKH address", func() {
+			err := ValidateBTCAddress("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it's a P2SH address", func() {
+			err := ValidateBTCAddress("3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it's a base58 address with a wrong checksum", func() {
+			err := ValidateBTCAddress("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3")
+
+			Convey("err should be errAddressChecksum", func() {
+				So(err, ShouldEqual, errAddressChecksum)
+			})
+		})
+
+		Convey("When it has characters outside of the alphabet", func() {
+			err := ValidateBTCAddress("0BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+
+			Convey("err should be errInvalidBTCAddress", func() {
+				So(err, ShouldEqual, errInvalidBTCAddress)
+			})
+		})
+
+		Convey("When it's a segwit v0 address", func() {
+			err := ValidateBTCAddress("BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it's a taproot address", func() {
+			err := ValidateBTCAddress("bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it's a segwit v0 address with a bech32m checksum", func() {
+			err := ValidateBTCAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh")
+
+			Convey("err should be errAddressChecksum", func() {
+				So(err, ShouldEqual, errAddressChecksum)
+			})
+		})
+
+		Convey("When it mixes cases", func() {
+			err := ValidateBTCAddress("bc1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4")
+
+			Convey("err should be errInvalidBTCAddress", func() {
+				So(err, ShouldEqual, errInvalidBTCAddress)
+			})
+		})
+	})
+}
+
+func TestValidateETHAddress(t *testing.T) {
+	Convey("Given an ETH address to validate", t, func() {
+		Convey("When it has a valid checksum", func() {
+			err := ValidateETHAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it's all lower case", func() {
+			err := ValidateETHAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it has a wrong checksum", func() {
+			err := ValidateETHAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+
+			Convey("err should be errAddressChecksum", func() {
+				So(err, ShouldEqual, errAddressChecksum)
+			})
+		})
+
+		Convey("When it's too short", func() {
+			err := ValidateETHAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA")
+
+			Convey("err should be errInvalidETHAddress", func() {
+				So(err, ShouldEqual, errInvalidETHAddress)
+			})
+		})
+	})
+}
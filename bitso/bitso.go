@@ -1,21 +1,19 @@
 package bitso
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
-	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
 const (
 	URL              = "https://api.bitso.com/v2/"
-	BTCMXN           = "btc_mxn"
-	ETHMXN           = "eth_mxn"
 	tickerPath       = "ticker"
 	transactionsPath = "transactions"
 	orderBookPath    = "order_book"
@@ -25,89 +23,279 @@ const (
 )
 
 type TickerInfo struct {
-	High      string
-	Last      string
-	Timestamp string
-	Volume    string
-	Vwap      string
-	Low       string
-	Ask       string
-	Bid       string
+	Book      Book
+	High      Decimal
+	Last      Decimal
+	Timestamp time.Time
+	Volume    Decimal
+	Vwap      Decimal
+	Low       Decimal
+	Ask       Decimal
+	Bid       Decimal
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (t *TickerInfo) UnmarshalJSON(data []byte) error {
+	type tickerInfo TickerInfo
+	v := &struct {
+		*tickerInfo
+		Timestamp timestamp
+		CreatedAt timestamp `json:"created_at"`
+	}{tickerInfo: (*tickerInfo)(t)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	t.Timestamp = v.Timestamp.Time()
+	t.CreatedAt = v.CreatedAt.Time()
+	return nil
 }
 
 type OrderBookInfo struct {
-	Asks [][]string
-	Bids [][]string
+	Asks      []*PriceLevel
+	Bids      []*PriceLevel
+	UpdatedAt time.Time `json:"updated_at"`
+	Sequence  int64     `json:"sequence,omitempty"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (o *OrderBookInfo) UnmarshalJSON(data []byte) error {
+	type orderBookInfo OrderBookInfo
+	v := &struct {
+		*orderBookInfo
+		UpdatedAt timestamp `json:"updated_at"`
+	}{orderBookInfo: (*orderBookInfo)(o)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	o.UpdatedAt = v.UpdatedAt.Time()
+	return nil
+}
+
+// PriceLevel is the amount available at a price. It's encoded
+// in JSON as a [price, amount] pair, like the v2 API does.
+type PriceLevel struct {
+	Price  Decimal
+	Amount Decimal
+}
+
+func (l PriceLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]Decimal{l.Price, l.Amount})
+}
+
+// UnmarshalJSON decodes [price, amount] pairs as well
+// as objects with price and amount keys.
+func (l *PriceLevel) UnmarshalJSON(data []byte) error {
+	var pair []Decimal
+	if err := json.Unmarshal(data, &pair); err == nil {
+		if len(pair) != 2 {
+			return errors.New("Invalid price level")
+		}
+		l.Price, l.Amount = pair[0], pair[1]
+		return nil
+	}
+	level := &struct {
+		Price  Decimal `json:"price"`
+		Amount Decimal `json:"amount"`
+	}{}
+	if err := json.Unmarshal(data, level); err != nil {
+		return err
+	}
+	l.Price, l.Amount = level.Price, level.Amount
+	return nil
+}
+
+// FullOrderBookInfo is an order book with every order on its own.
+type FullOrderBookInfo struct {
+	Asks      []*OrderBookEntry
+	Bids      []*OrderBookEntry
+	UpdatedAt time.Time
+	Sequence  int64
 }
 
+// OrderBookEntry is a single order of an order book.
+type OrderBookEntry struct {
+	Id     string
+	Price  Decimal
+	Amount Decimal
+}
+
+// Transaction is a trade of a book, Side is the side of the maker.
 type Transaction struct {
-	Amount string
-	Date   string
-	Price  string
+	Book   Book
+	Amount Decimal
+	Date   time.Time
+	Price  Decimal
 	Tid    int
 	Side   string
 }
 
-func Ticker(book string) (*TickerInfo, error) {
-	if validateBook(book) == false {
-		err := errors.New("Invalid book value")
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type transaction Transaction
+	v := &struct {
+		*transaction
+		Date timestamp
+	}{transaction: (*transaction)(t)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	t.Date = v.Date.Time()
+	return nil
+}
+
+// Ticker returns trading information from the specified book
+// using the DefaultClient.
+func Ticker(book Book) (*TickerInfo, error) {
+	return DefaultClient.Ticker(book)
+}
+
+// TickerContext is like Ticker with a context.
+func TickerContext(ctx context.Context, book Book) (*TickerInfo, error) {
+	return DefaultClient.TickerContext(ctx, book)
+}
+
+// Ticker returns trading information from the specified book.
+func (c *Client) Ticker(book Book) (*TickerInfo, error) {
+	return c.TickerContext(context.Background(), book)
+}
+
+// TickerContext is like Ticker with a context.
+func (c *Client) TickerContext(ctx context.Context, book Book) (*TickerInfo, error) {
+	if err := c.validateBook(ctx, book); err != nil {
 		return nil, err
 	}
 	ticker := &TickerInfo{}
 	v := &url.Values{}
-	v.Set("book", book)
-	err := get(tickerPath, v, ticker)
+	v.Set("book", book.String())
+	path := tickerPath
+	if c.Version == V3 {
+		path = tickerPathV3
+	}
+	err := c.get(ctx, path, v, ticker)
 	if err != nil {
 		return nil, err
 	}
 	return ticker, nil
 }
 
-func OrderBook(book string, group bool) (*OrderBookInfo, error) {
-	if validateBook(book) == false {
-		err := errors.New("Invalid book value")
+// OrderBook returns a list of all open orders in the specified book
+// using the DefaultClient.
+func OrderBook(book Book, group bool) (*OrderBookInfo, error) {
+	return DefaultClient.OrderBook(book, group)
+}
+
+// OrderBookContext is like OrderBook with a context.
+func OrderBookContext(ctx context.Context, book Book, group bool) (*OrderBookInfo, error) {
+	return DefaultClient.OrderBookContext(ctx, book, group)
+}
+
+// OrderBook returns a list of all open orders in the specified book.
+// When group is true the orders with the same price are aggregated
+// into a single level, otherwise every order has its own entry.
+func (c *Client) OrderBook(book Book, group bool) (*OrderBookInfo, error) {
+	return c.OrderBookContext(context.Background(), book, group)
+}
+
+// OrderBookContext is like OrderBook with a context.
+func (c *Client) OrderBookContext(ctx context.Context, book Book, group bool) (*OrderBookInfo, error) {
+	if err := c.validateBook(ctx, book); err != nil {
 		return nil, err
 	}
-	orderBook := &OrderBookInfo{}
 	v := &url.Values{}
-	v.Set("book", book)
-	err := get(orderBookPath, v, orderBook)
+	v.Set("book", book.String())
+	if c.Version == V3 {
+		v.Set("aggregate", strconv.FormatBool(group))
+		return c.orderBookV3(ctx, v)
+	}
+	v.Set("group", "0")
+	if group {
+		v.Set("group", "1")
+	}
+	orderBook := &OrderBookInfo{}
+	err := c.get(ctx, orderBookPath, v, orderBook)
 	if err != nil {
 		return nil, err
 	}
 	return orderBook, nil
 }
 
+// FullOrderBook returns every open order in the specified book
+// with its id using the DefaultClient.
+func FullOrderBook(book Book) (*FullOrderBookInfo, error) {
+	return DefaultClient.FullOrderBook(book)
+}
+
+// FullOrderBookContext is like FullOrderBook with a context.
+func FullOrderBookContext(ctx context.Context, book Book) (*FullOrderBookInfo, error) {
+	return DefaultClient.FullOrderBookContext(ctx, book)
+}
+
+// FullOrderBook returns every open order in the specified book with
+// its id. It's only available through the v3 API and fails for the
+// books that don't provide the ids.
+func (c *Client) FullOrderBook(book Book) (*FullOrderBookInfo, error) {
+	return c.FullOrderBookContext(context.Background(), book)
+}
+
+// FullOrderBookContext is like FullOrderBook with a context.
+func (c *Client) FullOrderBookContext(ctx context.Context, book Book) (*FullOrderBookInfo, error) {
+	if c.Version != V3 {
+		return nil, errV3Only
+	}
+	if err := c.validateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	orderBook, err := c.orderBookOrdersV3(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	return orderBook.fullOrderBookInfo()
+}
+
 /*
-GetTransactions returns a list of recent trades from the specified book
-and the specified time frame.
+Transactions returns a list of recent trades from the specified book
+and the specified time frame using the DefaultClient.
 
 Valid time frames are hour and minute. Leaving time blank will set hour as the default frame.
 */
-func Transactions(book string, time string) ([]*Transaction, error) {
+func Transactions(book Book, time string) ([]*Transaction, error) {
+	return DefaultClient.Transactions(book, time)
+}
+
+// TransactionsContext is like Transactions with a context.
+func TransactionsContext(ctx context.Context, book Book, time string) ([]*Transaction, error) {
+	return DefaultClient.TransactionsContext(ctx, book, time)
+}
+
+// Transactions returns a list of recent trades from the specified book
+// and the specified time frame.
+//
+// The v3 API has no time frames, so time is ignored and
+// the most recent trades are returned.
+func (c *Client) Transactions(book Book, time string) ([]*Transaction, error) {
+	return c.TransactionsContext(context.Background(), book, time)
+}
+
+// TransactionsContext is like Transactions with a context.
+func (c *Client) TransactionsContext(ctx context.Context, book Book, time string) ([]*Transaction, error) {
 	var transactions []*Transaction
-	if validateBook(book) == false {
-		err := errors.New("Invalid book value")
+	if err := c.validateBook(ctx, book); err != nil {
 		return nil, err
 	}
 	v := &url.Values{}
-	v.Set("book", book)
+	v.Set("book", book.String())
+	if c.Version == V3 {
+		return c.tradesV3(ctx, v)
+	}
 	v.Set("time", time)
-	err := get(transactionsPath, v, &transactions)
+	err := c.get(ctx, transactionsPath, v, &transactions)
 	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
-func getNonce() int64 {
-	return time.Now().UnixNano()
-}
-
-func validateBook(book string) bool {
-	return book == BTCMXN || book == ETHMXN
-}
-
 func sign(message, key string) string {
 	mac := hmac.New(sha256.New, []byte(key))
 	mac.Write([]byte(message))
@@ -115,27 +303,3 @@ func sign(message, key string) string {
 	s := hex.EncodeToString(bytes)
 	return s
 }
-
-func get(path string, query *url.Values, schema interface{}) error {
-	u, err := url.Parse(URL + path)
-	if err != nil {
-		return err
-	}
-	if query != nil {
-		u.RawQuery = query.Encode()
-	}
-	resp, err := http.Get(u.String())
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(body, schema)
-	if err != nil {
-		return err
-	}
-	return nil
-}
@@ -20,7 +20,11 @@ func TestBitso(t *testing.T) {
 			})
 
 			Convey("The price high should be 12700.00", func() {
-				So(ticker.High, ShouldEqual, "12700.00")
+				So(ticker.High.String(), ShouldEqual, "12700.00")
+			})
+
+			Convey("The timestamp should be decoded", func() {
+				So(ticker.Timestamp.Unix(), ShouldEqual, 1468809239)
 			})
 		})
 
@@ -32,7 +36,7 @@ func TestBitso(t *testing.T) {
 			})
 
 			Convey("The price high should be 213.97", func() {
-				So(ticker.High, ShouldEqual, "213.97")
+				So(ticker.High.String(), ShouldEqual, "213.97")
 			})
 		})
 
@@ -69,6 +73,23 @@ func TestBitso(t *testing.T) {
 				So(orderBook.Bids, ShouldHaveLength, 4)
 			})
 		})
+
+		Convey("And the orders are grouped", func() {
+			orderBook, err := OrderBook(BTCMXN, true)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+				So(orderBook.Bids, ShouldHaveLength, 5)
+			})
+		})
+
+		Convey("And the full order book is requested", func() {
+			_, err := FullOrderBook(BTCMXN)
+
+			Convey("err should be errV3Only", func() {
+				So(err, ShouldEqual, errV3Only)
+			})
+		})
 	})
 
 	Convey("When the last transactions are requested", t, func() {
@@ -121,18 +142,6 @@ func TestBitso(t *testing.T) {
 		})
 	})
 
-	Convey("Given a unique nonce", t, func() {
-		nonce := getNonce()
-
-		Convey("When a new nonce is generated", func() {
-			newNonce := getNonce()
-
-			Convey("The new nonce should be greater than the previous one", func() {
-				So(newNonce, ShouldBeGreaterThan, nonce)
-			})
-		})
-	})
-
 	Convey("Given a message to sign with a private key", t, func() {
 		message := "message"
 		key := "secret"
@@ -146,33 +155,3 @@ func TestBitso(t *testing.T) {
 		})
 	})
 }
-
-func Test_validateBook(t *testing.T) {
-	Convey("Given a book to validate", t, func() {
-		var validation bool
-
-		Convey("When the book is btc_mxn", func() {
-			validation = validateBook(BTCMXN)
-
-			Convey("validateBook should be true", func() {
-				So(validation, ShouldBeTrue)
-			})
-		})
-
-		Convey("When the book is eth_mxn", func() {
-			validation = validateBook(ETHMXN)
-
-			Convey("validateBook should be true", func() {
-				So(validation, ShouldBeTrue)
-			})
-		})
-
-		Convey("When the book is invalid", func() {
-			validation = validateBook("invalid_book")
-
-			Convey("validateBook should be false", func() {
-				So(validation, ShouldBeFalse)
-			})
-		})
-	})
-}
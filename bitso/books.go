@@ -0,0 +1,207 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+const availableBooksPathV3 = "available_books/"
+
+var (
+	errInvalidBook      = errors.New("Invalid book value")
+	errAmountOutOfRange = errors.New("Amount is out of the book limits")
+	errPriceOutOfRange  = errors.New("Price is out of the book limits")
+	errValueOutOfRange  = errors.New("Value is out of the book limits")
+)
+
+// BookInfo is a book open for trading and the limits of its
+// orders. Amounts are in the major currency, prices and values
+// in the minor one.
+type BookInfo struct {
+	Book          Book    `json:"book"`
+	MinimumAmount Decimal `json:"minimum_amount"`
+	MaximumAmount Decimal `json:"maximum_amount"`
+	MinimumPrice  Decimal `json:"minimum_price"`
+	MaximumPrice  Decimal `json:"maximum_price"`
+	MinimumValue  Decimal `json:"minimum_value"`
+	MaximumValue  Decimal `json:"maximum_value"`
+}
+
+// ValidateOrder checks amount, price and their value against the
+// limits of the book. A zero price, as in market orders, only
+// checks the amount.
+func (b *BookInfo) ValidateOrder(amount, price Decimal) error {
+	if !within(amount, b.MinimumAmount, b.MaximumAmount) {
+		return errAmountOutOfRange
+	}
+	if price.IsZero() {
+		return nil
+	}
+	if !within(price, b.MinimumPrice, b.MaximumPrice) {
+		return errPriceOutOfRange
+	}
+	if !within(amount.Mul(price), b.MinimumValue, b.MaximumValue) {
+		return errValueOutOfRange
+	}
+	return nil
+}
+
+// within tells whether d is between min and max,
+// a zero max means there's no upper limit.
+func within(d, min, max Decimal) bool {
+	if d.Cmp(min) < 0 {
+		return false
+	}
+	return max.IsZero() || d.Cmp(max) <= 0
+}
+
+// AvailableBooks returns the books open for trading
+// using the DefaultClient.
+func AvailableBooks() ([]*BookInfo, error) {
+	return DefaultClient.AvailableBooks()
+}
+
+// AvailableBooksContext is like AvailableBooks with a context.
+func AvailableBooksContext(ctx context.Context) ([]*BookInfo, error) {
+	return DefaultClient.AvailableBooksContext(ctx)
+}
+
+/*
+AvailableBooks returns the books open for trading.
+
+They are fetched from the v3 API the first time they are needed and
+cached on the client, RefreshBooks fetches them again.
+*/
+func (c *Client) AvailableBooks() ([]*BookInfo, error) {
+	return c.AvailableBooksContext(context.Background())
+}
+
+// AvailableBooksContext is like AvailableBooks with a context.
+func (c *Client) AvailableBooksContext(ctx context.Context) ([]*BookInfo, error) {
+	if books := c.cachedBooks(); books != nil {
+		return books, nil
+	}
+	if err := c.RefreshBooksContext(ctx); err != nil {
+		return nil, err
+	}
+	return c.cachedBooks(), nil
+}
+
+// cachedBooks returns a copy of the cached books, so
+// the callers can't change them. It's nil when there are none.
+func (c *Client) cachedBooks() []*BookInfo {
+	c.booksMutex.Lock()
+	defer c.booksMutex.Unlock()
+	if len(c.books) == 0 {
+		return nil
+	}
+	books := make([]*BookInfo, len(c.books))
+	for i, b := range c.books {
+		book := *b
+		books[i] = &book
+	}
+	return books
+}
+
+// RefreshBooks replaces the cached books with the ones
+// currently open for trading.
+func (c *Client) RefreshBooks() error {
+	return c.RefreshBooksContext(context.Background())
+}
+
+// RefreshBooksContext is like RefreshBooks with a context. The
+// books are fetched without holding the cache, an empty list
+// leaves it as if they were never fetched.
+func (c *Client) RefreshBooksContext(ctx context.Context) error {
+	books, err := c.fetchBooks(ctx)
+	if err != nil {
+		return err
+	}
+	c.booksMutex.Lock()
+	c.books = books
+	c.booksMutex.Unlock()
+	return nil
+}
+
+// LookupBook returns the limits of book.
+func (c *Client) LookupBook(book Book) (*BookInfo, error) {
+	return c.LookupBookContext(context.Background(), book)
+}
+
+// LookupBookContext is like LookupBook with a context.
+func (c *Client) LookupBookContext(ctx context.Context, book Book) (*BookInfo, error) {
+	books, err := c.AvailableBooksContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range books {
+		if b.Book == book {
+			return b, nil
+		}
+	}
+	return nil, errInvalidBook
+}
+
+/*
+validateBook checks that book is open for trading.
+
+The v2 API doesn't list the books, so the calls of v2 clients don't
+fail when the v3 API can't list them: book is checked against the
+known books instead.
+*/
+func (c *Client) validateBook(ctx context.Context, book Book) error {
+	books, err := c.AvailableBooksContext(ctx)
+	if books == nil && c.Version != V3 {
+		if !knownBooks[book] {
+			return errInvalidBook
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, b := range books {
+		if b.Book == book {
+			return nil
+		}
+	}
+	return errInvalidBook
+}
+
+// booksURL returns the URL the available books are fetched from.
+// For v2 clients it's the v3 API next to URL, like
+// https://api.bitso.com/v3/ for https://api.bitso.com/v2/.
+func (c *Client) booksURL() string {
+	if c.BooksURL != "" {
+		return c.BooksURL
+	}
+	u := c.baseURL()
+	if c.Version != V3 && strings.HasSuffix(u, "/v2/") {
+		u = strings.TrimSuffix(u, "v2/") + "v3/"
+	}
+	return u + availableBooksPathV3
+}
+
+// fetchBooks returns the available books, nil when there are
+// none. The v2 API doesn't list them, so they are always
+// fetched from the v3 API.
+func (c *Client) fetchBooks(ctx context.Context) ([]*BookInfo, error) {
+	u := c.booksURL()
+	var body []byte
+	err := c.retry(ctx, true, func() (err error) {
+		body, err = c.do(ctx, c.PublicLimit, "GET", u, nil, nil)
+		return err
+	})
+	if err != nil {
+		return nil, withResponse(err, availableBooksPathV3, body)
+	}
+	var books []*BookInfo
+	if err := decodeEnvelope(body, &books); err != nil {
+		return nil, withResponse(err, availableBooksPathV3, body)
+	}
+	if len(books) == 0 {
+		return nil, nil
+	}
+	return books, nil
+}
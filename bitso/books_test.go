@@ -0,0 +1,221 @@
+package bitso
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const availableBooks = `[
+	{
+		"book": "btc_mxn",
+		"minimum_amount": "0.00015",
+		"maximum_amount": "5000.00",
+		"minimum_price": "1.00",
+		"maximum_price": "5000000.00",
+		"minimum_value": "1.00",
+		"maximum_value": "100000000.00"
+	},
+	{
+		"book": "eth_mxn",
+		"minimum_amount": "0.001",
+		"maximum_amount": "5000.00",
+		"minimum_price": "1.00",
+		"maximum_price": "5000000.00",
+		"minimum_value": "1.00",
+		"maximum_value": "100000000.00"
+	},
+	{
+		"book": "xrp_mxn",
+		"minimum_amount": "1",
+		"maximum_amount": "0",
+		"minimum_price": "0.01",
+		"maximum_price": "0",
+		"minimum_value": "5",
+		"maximum_value": "0"
+	}
+]`
+
+func registerBooksResponder() {
+	httpmock.RegisterResponder("GET", URLv3+availableBooksPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			return v3Response(availableBooks)
+		},
+	)
+}
+
+func TestBooks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a client using the v2 API", t, func() {
+		client := NewClient()
+		calls := 0
+		payload := availableBooks
+		httpmock.RegisterResponder("GET", URLv3+availableBooksPathV3,
+			func(req *http.Request) (*http.Response, error) {
+				calls++
+				return v3Response(payload)
+			},
+		)
+
+		Convey("When the available books are requested twice", func() {
+			books, err := client.AvailableBooks()
+			So(err, ShouldBeNil)
+			_, err = client.AvailableBooks()
+			So(err, ShouldBeNil)
+
+			Convey("They should be fetched from the v3 API once", func() {
+				So(books, ShouldHaveLength, 3)
+				So(books[2].Book, ShouldEqual, XRPMXN)
+				So(books[0].MinimumAmount.String(), ShouldEqual, "0.00015")
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a book listed by the API is validated", func() {
+			err := client.validateBook(context.Background(), "xrp_mxn")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When an unknown book is validated", func() {
+			err := client.validateBook(context.Background(), "invalid_book")
+
+			Convey("err should be errInvalidBook", func() {
+				So(err, ShouldEqual, errInvalidBook)
+			})
+		})
+
+		Convey("When a new market is validated before the books are fetched", func() {
+			payload = `[{"book": "sol_mxn", "minimum_amount": "0.01"}]`
+			err := client.validateBook(context.Background(), "sol_mxn")
+
+			Convey("It should be checked against the fetched books", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the returned books are changed", func() {
+			books, err := client.AvailableBooks()
+			So(err, ShouldBeNil)
+			books[0].Book = "changed"
+			books[1] = &BookInfo{Book: "changed"}
+
+			Convey("The cache should be unchanged", func() {
+				So(client.validateBook(context.Background(), BTCMXN), ShouldBeNil)
+				So(client.validateBook(context.Background(), ETHMXN), ShouldBeNil)
+				So(client.validateBook(context.Background(), "changed"), ShouldEqual, errInvalidBook)
+			})
+		})
+
+		Convey("When the API lists no books", func() {
+			payload = `[]`
+			_, err := client.AvailableBooks()
+			So(err, ShouldBeNil)
+			payload = availableBooks
+			err = client.validateBook(context.Background(), XRPMXN)
+
+			Convey("They should be fetched again", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the books are refreshed", func() {
+			_, err := client.AvailableBooks()
+			So(err, ShouldBeNil)
+			payload = `[{"book": "ltc_mxn", "minimum_amount": "0.01"}]`
+			err = client.RefreshBooks()
+
+			Convey("The new books should be cached", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 2)
+				So(client.validateBook(context.Background(), "ltc_mxn"), ShouldBeNil)
+				So(client.validateBook(context.Background(), BTCMXN), ShouldEqual, errInvalidBook)
+			})
+		})
+	})
+
+	Convey("Given a v2 client pointing to a staging host", t, func() {
+		httpmock.Reset()
+		client := NewClient()
+		client.URL = "https://staging.bitso.test/v2/"
+		httpmock.RegisterResponder("GET", client.URL+tickerPath,
+			httpmock.NewStringResponder(200, `{"high": "1.00"}`))
+
+		Convey("When the ticker is requested and the books can't be fetched", func() {
+			_, err := client.Ticker(BTCMXN)
+			_, invalidErr := client.Ticker("invalid_book")
+
+			Convey("The book should be checked against the known books", func() {
+				So(err, ShouldBeNil)
+				So(invalidErr, ShouldEqual, errInvalidBook)
+			})
+		})
+
+		Convey("When the available books are requested", func() {
+			httpmock.RegisterResponder("GET", "https://staging.bitso.test/v3/"+availableBooksPathV3,
+				func(req *http.Request) (*http.Response, error) {
+					return v3Response(availableBooks)
+				},
+			)
+			books, err := client.AvailableBooks()
+
+			Convey("They should be fetched from the v3 API of the staging host", func() {
+				So(err, ShouldBeNil)
+				So(books, ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("When the books URL is configured", func() {
+			client.BooksURL = "https://books.bitso.test/available_books/"
+
+			Convey("It should be used", func() {
+				So(client.booksURL(), ShouldEqual, client.BooksURL)
+			})
+		})
+	})
+
+	Convey("Given the limits of a book", t, func() {
+		info := &BookInfo{
+			Book:          BTCMXN,
+			MinimumAmount: MustParseDecimal("0.00015"),
+			MaximumAmount: MustParseDecimal("500"),
+			MinimumPrice:  MustParseDecimal("100"),
+			MaximumPrice:  MustParseDecimal("5000000"),
+			MinimumValue:  MustParseDecimal("5"),
+		}
+
+		Convey("An order within them should be valid", func() {
+			So(info.ValidateOrder(MustParseDecimal("0.01"), MustParseDecimal("5600")), ShouldBeNil)
+		})
+
+		Convey("A market order should only check the amount", func() {
+			So(info.ValidateOrder(MustParseDecimal("0.0002"), Decimal{}), ShouldBeNil)
+			So(info.ValidateOrder(MustParseDecimal("501"), Decimal{}), ShouldEqual, errAmountOutOfRange)
+		})
+
+		Convey("An amount below the minimum should be invalid", func() {
+			So(info.ValidateOrder(MustParseDecimal("0.0001"), MustParseDecimal("5600")), ShouldEqual, errAmountOutOfRange)
+		})
+
+		Convey("A price above the maximum should be invalid", func() {
+			So(info.ValidateOrder(MustParseDecimal("0.01"), MustParseDecimal("6000000")), ShouldEqual, errPriceOutOfRange)
+		})
+
+		Convey("A value below the minimum should be invalid", func() {
+			So(info.ValidateOrder(MustParseDecimal("0.0002"), MustParseDecimal("5600")), ShouldEqual, errValueOutOfRange)
+		})
+
+		Convey("A zero maximum value should mean no limit", func() {
+			So(info.ValidateOrder(MustParseDecimal("500"), MustParseDecimal("5000000")), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package bitso
+
+import (
+	"errors"
+)
+
+const clabeLength = 18
+
+var (
+	errInvalidCLABE  = errors.New("Invalid CLABE, it must have 18 digits")
+	errCLABEChecksum = errors.New("Invalid CLABE control digit")
+	errUnknownBank   = errors.New("Unknown CLABE bank code")
+)
+
+// clabeWeights are applied cyclically to the first 17 digits
+// to compute the control digit.
+var clabeWeights = [3]int{3, 7, 1}
+
+// clabeBankNames maps the bank codes of the SPEI participants
+// to their names.
+var clabeBankNames = map[string]string{
+	"002": "BANAMEX",
+	"006": "BANCOMEXT",
+	"009": "BANOBRAS",
+	"012": "BBVA MEXICO",
+	"014": "SANTANDER",
+	"019": "BANJERCITO",
+	"021": "HSBC",
+	"030": "BAJIO",
+	"036": "INBURSA",
+	"042": "MIFEL",
+	"044": "SCOTIABANK",
+	"058": "BANREGIO",
+	"059": "INVEX",
+	"060": "BANSI",
+	"062": "AFIRME",
+	"072": "BANORTE",
+	"106": "BANK OF AMERICA",
+	"108": "MUFG",
+	"110": "JP MORGAN",
+	"112": "BMONEX",
+	"113": "VE POR MAS",
+	"127": "AZTECA",
+	"128": "AUTOFIN",
+	"129": "BARCLAYS",
+	"130": "COMPARTAMOS",
+	"132": "MULTIVA BANCO",
+	"133": "ACTINVER",
+	"135": "NAFIN",
+	"136": "INTERCAM BANCO",
+	"137": "BANCOPPEL",
+	"138": "ABC CAPITAL",
+	"140": "CONSUBANCO",
+	"141": "VOLKSWAGEN",
+	"143": "CIBANCO",
+	"145": "BBASE",
+	"147": "BANKAOOL",
+	"148": "PAGATODO",
+	"150": "INMOBILIARIO",
+	"151": "DONDE",
+	"152": "BANCREA",
+	"154": "BANCO COVALTO",
+	"155": "ICBC",
+	"156": "SABADELL",
+	"157": "SHINHAN",
+	"158": "MIZUHO BANK",
+	"159": "BANK OF CHINA",
+	"160": "BANCO S3",
+	"166": "BANCO DEL BIENESTAR",
+	"168": "HIPOTECARIA FEDERAL",
+	"600": "MONEXCB",
+	"601": "GBM",
+	"602": "MASARI",
+	"605": "VALUE",
+	"608": "VECTOR",
+	"616": "FINAMEX",
+	"617": "VALMEX",
+	"620": "PROFUTURO",
+	"630": "CB INTERCAM",
+	"631": "CI BOLSA",
+	"634": "FINCOMUN",
+	"638": "NU MEXICO",
+	"646": "STP",
+	"652": "CREDICAPITAL",
+	"653": "KUSPIT",
+	"656": "UNAGRA",
+	"659": "ASP INTEGRA OPC",
+	"670": "LIBERTAD",
+	"677": "CAJA POP MEXICA",
+	"680": "CRISTOBAL COLON",
+	"683": "CAJA TELEFONIST",
+	"684": "TRANSFER",
+	"685": "FONDO FIRA",
+	"686": "INVERCAP",
+	"689": "FOMPED",
+	"703": "TESORED",
+	"706": "ARCUS",
+	"710": "NVIO",
+	"722": "MERCADO PAGO",
+	"723": "CUENCA",
+	"728": "SPIN BY OXXO",
+	"902": "INDEVAL",
+}
+
+// CLABE is a parsed Mexican standardized bank account number.
+type CLABE struct {
+	Number   string
+	Bank     string
+	BankName string
+	City     string
+	Account  string
+	Control  int
+}
+
+/*
+ParseCLABE validates clabe and splits it into its parts.
+
+The first 3 digits are the bank code, which must belong to a known
+SPEI participant, followed by 3 digits of the city, 11 digits of the
+account and the control digit.
+*/
+func ParseCLABE(clabe string) (*CLABE, error) {
+	if len(clabe) != clabeLength {
+		return nil, errInvalidCLABE
+	}
+	sum := 0
+	for i := 0; i < clabeLength; i++ {
+		if clabe[i] < '0' || clabe[i] > '9' {
+			return nil, errInvalidCLABE
+		}
+		if i < clabeLength-1 {
+			sum += int(clabe[i]-'0') * clabeWeights[i%3] % 10
+		}
+	}
+	control := (10 - sum%10) % 10
+	if int(clabe[clabeLength-1]-'0') != control {
+		return nil, errCLABEChecksum
+	}
+	bankName, ok := clabeBankNames[clabe[:3]]
+	if !ok {
+		return nil, errUnknownBank
+	}
+	c := &CLABE{
+		Number:   clabe,
+		Bank:     clabe[:3],
+		BankName: bankName,
+		City:     clabe[3:6],
+		Account:  clabe[6:17],
+		Control:  control,
+	}
+	return c, nil
+}
+
+// ValidateCLABE checks the length, control digit and bank code of clabe.
+func ValidateCLABE(clabe string) error {
+	_, err := ParseCLABE(clabe)
+	return err
+}
@@ -0,0 +1,67 @@
+package bitso
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseCLABE(t *testing.T) {
+	Convey("Given a CLABE to parse", t, func() {
+		Convey("When it's valid", func() {
+			clabe, err := ParseCLABE("002010077777777771")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("It should be split into its parts", func() {
+				So(clabe.Bank, ShouldEqual, "002")
+				So(clabe.BankName, ShouldEqual, "BANAMEX")
+				So(clabe.City, ShouldEqual, "010")
+				So(clabe.Account, ShouldEqual, "07777777777")
+				So(clabe.Control, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the control digit is zero", func() {
+			err := ValidateCLABE("646180157000000004")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When the control digit is wrong", func() {
+			err := ValidateCLABE("002010077777777779")
+
+			Convey("err should be errCLABEChecksum", func() {
+				So(err, ShouldEqual, errCLABEChecksum)
+			})
+		})
+
+		Convey("When it's too short", func() {
+			err := ValidateCLABE("00201007777777777")
+
+			Convey("err should be errInvalidCLABE", func() {
+				So(err, ShouldEqual, errInvalidCLABE)
+			})
+		})
+
+		Convey("When it has letters", func() {
+			err := ValidateCLABE("00201007777777777a")
+
+			Convey("err should be errInvalidCLABE", func() {
+				So(err, ShouldEqual, errInvalidCLABE)
+			})
+		})
+
+		Convey("When the bank code is unknown", func() {
+			err := ValidateCLABE("999010077777777774")
+
+			Convey("err should be errUnknownBank", func() {
+				So(err, ShouldEqual, errUnknownBank)
+			})
+		})
+	})
+}
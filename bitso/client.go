@@ -0,0 +1,151 @@
+package bitso
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const defaultUserAgent = "gitso"
+
+// Client owns the configuration used to reach the Bitso API.
+// The zero value is ready to use and talks to the v2 API with
+// http.DefaultClient.
+type Client struct {
+	// Version selects the API version used by every call.
+	Version APIVersion
+	// URL is the base URL every path is appended to.
+	// Leaving it blank uses URL or URLv3 depending on Version.
+	URL string
+	// BooksURL is the URL of the v3 available books, the v2 API
+	// doesn't list them. Leaving it blank derives it from URL.
+	BooksURL string
+	// StreamURL is the address of the websocket feed.
+	// Leaving it blank uses the package StreamURL.
+	StreamURL string
+	// HTTPClient is used to perform the requests.
+	// Leaving it nil uses http.DefaultClient.
+	HTTPClient *http.Client
+	// UserAgent is sent in the User-Agent header.
+	UserAgent string
+	// Timeout limits the duration of every request.
+	// Zero means no timeout besides the HTTPClient's own.
+	// Every attempt of a retried call has its own timeout.
+	Timeout time.Duration
+	// Retry is the policy of the calls that are safe to repeat.
+	// Leaving it nil uses DefaultRetryPolicy.
+	Retry *RetryPolicy
+	// PublicLimit and PrivateLimit are the budgets of the public
+	// and private requests, shared by every goroutine using the
+	// client. Leaving them nil doesn't limit the requests.
+	PublicLimit  *RateLimiter
+	PrivateLimit *RateLimiter
+
+	booksMutex sync.Mutex
+	books      []*BookInfo
+}
+
+// DefaultClient is the Client used by the package level functions.
+var DefaultClient = NewClient()
+
+// NewClient returns a Client with the default configuration.
+func NewClient() *Client {
+	return &Client{
+		UserAgent: defaultUserAgent,
+	}
+}
+
+// Authenticate receives a Keys used to
+// authenticate into the private endpoints through c.
+func (c *Client) Authenticate(keys *Keys) *Account {
+	return &Account{keys: keys, client: c}
+}
+
+func (c *Client) baseURL() string {
+	if c.URL == "" {
+		if c.Version == V3 {
+			return URLv3
+		}
+		return URL
+	}
+	return c.URL
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) get(ctx context.Context, path string, query *url.Values, schema interface{}) error {
+	u, err := url.Parse(c.baseURL() + path)
+	if err != nil {
+		return err
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+	var body []byte
+	err = c.retry(ctx, true, func() error {
+		body, err = c.do(ctx, c.PublicLimit, "GET", u.String(), nil, nil)
+		return err
+	})
+	if err != nil {
+		return withResponse(err, path, body)
+	}
+	if c.Version == V3 {
+		return withResponse(decodeEnvelope(body, schema), path, body)
+	}
+	return json.Unmarshal(body, schema)
+}
+
+func (c *Client) post(ctx context.Context, path string, payload []byte) ([]byte, error) {
+	return c.do(ctx, c.PrivateLimit, "POST", c.baseURL()+path, payload, nil)
+}
+
+// do performs a request once limiter allows it.
+func (c *Client) do(ctx context.Context, limiter *RateLimiter, method, u string, payload []byte, header http.Header) ([]byte, error) {
+	if err := limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	var r io.Reader
+	if payload != nil {
+		r = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	limiter.update(resp.StatusCode, resp.Header)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body, responseError(resp.StatusCode, body)
+}
@@ -0,0 +1,107 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a client pointing to a staging host", t, func() {
+		client := NewClient()
+		client.URL = "https://staging.bitso.test/v2/"
+		client.UserAgent = "tests"
+		var userAgent string
+		httpmock.RegisterResponder("GET", client.URL+tickerPath,
+			func(req *http.Request) (*http.Response, error) {
+				userAgent = req.Header.Get("User-Agent")
+				return httpmock.NewJsonResponse(200, &TickerInfo{High: MustParseDecimal("1.00")})
+			},
+		)
+
+		Convey("When the ticker is requested", func() {
+			ticker, err := client.Ticker(BTCMXN)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The request should be sent to the staging host", func() {
+				So(ticker.High.String(), ShouldEqual, "1.00")
+			})
+
+			Convey("The user agent should be sent", func() {
+				So(userAgent, ShouldEqual, "tests")
+			})
+		})
+	})
+
+	Convey("Given a context", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+		ctx, cancel := context.WithCancel(context.Background())
+		var contexts []context.Context
+		responder := func(req *http.Request) (*http.Response, error) {
+			contexts = append(contexts, req.Context())
+			if err := req.Context().Err(); err != nil {
+				return nil, err
+			}
+			return v3Response(`{}`)
+		}
+		httpmock.RegisterResponder("GET", URLv3+tickerPathV3, responder)
+		httpmock.RegisterResponder("GET", URLv3+balancePathV3, responder)
+
+		Convey("When public and private calls are made with it", func() {
+			_, err := client.TickerContext(ctx, BTCMXN)
+			So(err, ShouldBeNil)
+			_, err = account.BalanceContext(ctx)
+			So(err, ShouldBeNil)
+
+			Convey("It should reach the HTTP requests", func() {
+				So(contexts, ShouldHaveLength, 2)
+				cancel()
+				for _, c := range contexts {
+					So(c.Err(), ShouldEqual, context.Canceled)
+				}
+			})
+		})
+
+		Convey("When it's cancelled before a call", func() {
+			cancel()
+			_, err := client.TickerContext(ctx, BTCMXN)
+
+			Convey("err should be context.Canceled", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a client without configuration", t, func() {
+		client := &Client{}
+
+		Convey("The base URL should be the package URL", func() {
+			So(client.baseURL(), ShouldEqual, URL)
+		})
+
+		Convey("The HTTP client should be the default one", func() {
+			So(client.httpClient(), ShouldEqual, http.DefaultClient)
+		})
+
+		Convey("When an account is authenticated through it", func() {
+			account := client.Authenticate(&Keys{})
+
+			Convey("The account should use the client", func() {
+				So(account.client, ShouldEqual, client)
+			})
+		})
+	})
+}
@@ -0,0 +1,284 @@
+package bitso
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxScale bounds the scale of the decimals parsed, and of the power of
+// ten their digits are multiplied by, so exponents can't overflow it or
+// allocate huge integers.
+const maxScale = 1000
+
+var (
+	errInvalidDecimal = errors.New("Invalid decimal value")
+	ten               = big.NewInt(10)
+)
+
+/*
+Decimal is an exact, arbitrary precision decimal number used for
+every price and amount.
+
+It's the unscaled integer divided by 10^scale, so the digits sent by
+the exchange are kept as they are, trailing zeros included. The scale
+is never negative, places below zero in Div, Round, Truncate and
+StringFixed round to tens, hundreds and so on. The zero
+value is 0. Decimals are immutable, every operation returns a new one.
+
+Decimals are encoded in JSON as strings and decoded from strings,
+numbers or null.
+*/
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NewDecimal returns unscaled / 10^scale.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{unscaled: new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))}
+	}
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses s, a decimal number with an optional sign,
+// fractional part and exponent, like "-12.50" or "1e-8".
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, errInvalidDecimal
+		}
+		mantissa, exponent = s[:i], e
+	}
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+	}
+	sign := ""
+	if len(integer) > 0 && (integer[0] == '-' || integer[0] == '+') {
+		sign, integer = integer[:1], integer[1:]
+	}
+	digits := integer + fraction
+	if digits == "" {
+		return Decimal{}, errInvalidDecimal
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Decimal{}, errInvalidDecimal
+		}
+	}
+	unscaled, _ := new(big.Int).SetString(sign+digits, 10)
+	scale := int64(len(fraction)) - exponent
+	if scale > maxScale || scale < -maxScale {
+		return Decimal{}, errInvalidDecimal
+	}
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s can't be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns the unscaled value of d with scale digits,
+// scale must not be lower than d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+func align(d1, d2 Decimal) (*big.Int, *big.Int, int32) {
+	scale := d1.scale
+	if d2.scale > scale {
+		scale = d2.scale
+	}
+	return d1.rescale(scale), d2.rescale(scale), scale
+}
+
+// Add returns d + d2.
+func (d Decimal) Add(d2 Decimal) Decimal {
+	x, y, scale := align(d, d2)
+	return Decimal{unscaled: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub returns d - d2.
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	x, y, scale := align(d, d2)
+	return Decimal{unscaled: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul returns d * d2.
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), d2.int()), scale: d.scale + d2.scale}
+}
+
+// Div returns d / d2 rounded half away from zero to places decimals.
+// It panics if d2 is zero.
+func (d Decimal) Div(d2 Decimal, places int32) Decimal {
+	num := new(big.Int).Mul(d.int(), pow10(d2.scale))
+	den := new(big.Int).Mul(d2.int(), pow10(d.scale))
+	if places >= 0 {
+		num.Mul(num, pow10(places))
+	} else {
+		den.Mul(den, pow10(-places))
+	}
+	return scaled(quoRound(num, den), places)
+}
+
+// scaled returns unscaled / 10^scale, multiplying
+// unscaled instead when scale is negative.
+func scaled(unscaled *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{unscaled: unscaled.Mul(unscaled, pow10(-scale))}
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// quoRound returns num / den rounded half away from zero.
+func quoRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign() == den.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Round returns d rounded half away from zero to places decimals.
+// Decimals with fewer decimals are returned as they are.
+func (d Decimal) Round(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	return scaled(quoRound(d.int(), pow10(d.scale-places)), places)
+}
+
+// Truncate returns d without the decimals beyond places.
+func (d Decimal) Truncate(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	return scaled(new(big.Int).Quo(d.int(), pow10(d.scale-places)), places)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Cmp returns -1, 0 or 1 when d is lower, equal or greater than d2.
+func (d Decimal) Cmp(d2 Decimal) int {
+	x, y, _ := align(d, d2)
+	return x.Cmp(y)
+}
+
+// Equal tells whether d and d2 are the same number,
+// regardless of their trailing zeros.
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+// Sign returns -1, 0 or 1 when d is negative, zero or positive.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero tells whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of decimals of d.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String formats d with all of its decimals.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed formats d rounded to exactly places decimals.
+func (d Decimal) StringFixed(places int32) string {
+	r := d.Round(places)
+	if places < 0 {
+		return r.String()
+	}
+	return Decimal{unscaled: r.rescale(places), scale: places}.String()
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number, null and
+// empty strings are decoded as zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,134 @@
+package bitso
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDecimal(t *testing.T) {
+	Convey("Given decimals to parse", t, func() {
+		Convey("When they have trailing zeros", func() {
+			d, err := ParseDecimal("5600.00")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The zeros should be kept", func() {
+				So(d.String(), ShouldEqual, "5600.00")
+				So(d.Scale(), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When they have a sign and an exponent", func() {
+			So(MustParseDecimal("-0.25").String(), ShouldEqual, "-0.25")
+			So(MustParseDecimal("1e-8").String(), ShouldEqual, "0.00000001")
+			So(MustParseDecimal("1.5E3").String(), ShouldEqual, "1500")
+			So(MustParseDecimal(".5").String(), ShouldEqual, "0.5")
+		})
+
+		Convey("When they aren't numbers", func() {
+			for _, s := range []string{"", "-", "1.2.3", "abc", "1e", "0x10"} {
+				_, err := ParseDecimal(s)
+				So(err, ShouldEqual, errInvalidDecimal)
+			}
+		})
+
+		Convey("When their exponent is out of range", func() {
+			for _, s := range []string{"1e-2147483648", "1e2000000000", "1e1001", "1e-1001"} {
+				_, err := ParseDecimal(s)
+				So(err, ShouldEqual, errInvalidDecimal)
+			}
+			var d Decimal
+			So(json.Unmarshal([]byte("1e2000000000"), &d), ShouldEqual, errInvalidDecimal)
+			So(MustParseDecimal("1e1000").Scale(), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given two decimals", t, func() {
+		a := MustParseDecimal("0.1")
+		b := MustParseDecimal("0.2")
+
+		Convey("Their sum should be exact", func() {
+			So(a.Add(b).String(), ShouldEqual, "0.3")
+			So(a.Add(b).Equal(MustParseDecimal("0.30")), ShouldBeTrue)
+		})
+
+		Convey("Their difference, product and quotient should be exact", func() {
+			So(a.Sub(b).String(), ShouldEqual, "-0.1")
+			So(a.Mul(b).String(), ShouldEqual, "0.02")
+			So(a.Div(b, 2).String(), ShouldEqual, "0.50")
+			So(MustParseDecimal("2").Div(MustParseDecimal("3"), 4).String(), ShouldEqual, "0.6667")
+		})
+
+		Convey("They should be compared by value", func() {
+			So(a.Cmp(b), ShouldEqual, -1)
+			So(b.Cmp(a), ShouldEqual, 1)
+			So(MustParseDecimal("1.0").Cmp(MustParseDecimal("1")), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a decimal to round", t, func() {
+		d := MustParseDecimal("-1.2350")
+
+		Convey("Halves should be rounded away from zero", func() {
+			So(d.Round(2).String(), ShouldEqual, "-1.24")
+			So(d.Truncate(2).String(), ShouldEqual, "-1.23")
+		})
+
+		Convey("Fixed formatting should pad the decimals", func() {
+			So(MustParseDecimal("7").StringFixed(2), ShouldEqual, "7.00")
+			So(d.StringFixed(1), ShouldEqual, "-1.2")
+		})
+
+		Convey("Negative places should round to tens", func() {
+			n := MustParseDecimal("125.5")
+			So(n.Round(-1).String(), ShouldEqual, "130")
+			So(n.Round(-1).Float64(), ShouldEqual, 130)
+			So(n.Truncate(-1).String(), ShouldEqual, "120")
+			So(MustParseDecimal("125").Round(-1).String(), ShouldEqual, "130")
+			So(n.StringFixed(-2), ShouldEqual, "100")
+			So(n.Div(MustParseDecimal("0.5"), -2).String(), ShouldEqual, "300")
+		})
+	})
+
+	Convey("Given the zero value", t, func() {
+		var d Decimal
+
+		Convey("It should be zero", func() {
+			So(d.IsZero(), ShouldBeTrue)
+			So(d.String(), ShouldEqual, "0")
+			So(d.Add(NewDecimal(15, 1)).String(), ShouldEqual, "1.5")
+		})
+	})
+
+	Convey("Given a JSON object with decimals", t, func() {
+		var v struct {
+			String Decimal `json:"string"`
+			Number Decimal `json:"number"`
+			Null   Decimal `json:"null"`
+		}
+		err := json.Unmarshal([]byte(`{"string": "0.00134000", "number": 12.5, "null": null}`), &v)
+
+		Convey("err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Strings, numbers and null should be decoded", func() {
+			So(v.String.String(), ShouldEqual, "0.00134000")
+			So(v.Number.String(), ShouldEqual, "12.5")
+			So(v.Null.IsZero(), ShouldBeTrue)
+		})
+
+		Convey("When it's encoded", func() {
+			data, err := json.Marshal(v.String)
+
+			Convey("It should be a string", func() {
+				So(err, ShouldBeNil)
+				So(string(data), ShouldEqual, `"0.00134000"`)
+			})
+		})
+	})
+}
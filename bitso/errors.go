@@ -0,0 +1,195 @@
+package bitso
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors matching the *APIError of every code of the
+// catalog they stand for, use them with errors.Is.
+var (
+	ErrInvalidSignature  = errors.New("Invalid signature")
+	ErrInvalidNonce      = errors.New("Invalid nonce")
+	ErrInsufficientFunds = errors.New("Insufficient funds")
+	ErrBelowMinimum      = errors.New("Below the minimum")
+	ErrOrderNotFound     = errors.New("Order not found")
+	ErrRateLimited       = errors.New("Rate limited")
+	ErrNotJSON           = errors.New("Response isn't JSON")
+)
+
+// maxErrorBody is the number of bytes of the body kept by an *HTTPError.
+const maxErrorBody = 512
+
+// errorCodes is the catalog of the codes of the v2 and v3 APIs,
+// with the message each one is documented with.
+var errorCodes = map[string][]error{
+	// v2
+	"101": {ErrInvalidSignature}, // Invalid API Code or Invalid Signature
+	"102": {ErrInvalidNonce},     // Invalid Nonce
+	"108": {ErrOrderNotFound},    // Order not found
+	// v3
+	"0201": {ErrInvalidSignature, ErrInvalidNonce}, // Invalid Nonce or Invalid Credentials
+	"0205": {ErrInvalidSignature},                  // Invalid Message Signature
+	"0303": {ErrBelowMinimum},                      // Incorrect price, below the minimum
+	"0305": {ErrBelowMinimum},                      // Incorrect major, below the minimum
+	"0307": {ErrBelowMinimum},                      // Incorrect minor, below the minimum
+	"0379": {ErrInsufficientFunds},                 // Insufficient funds
+	"0404": {ErrOrderNotFound},                     // Order not found
+}
+
+// errorMessages classifies the errors by their message when their
+// code isn't in the catalog, like the v2 code 4 shared by unrelated
+// errors.
+var errorMessages = []struct {
+	substring string
+	err       error
+}{
+	{"insufficient", ErrInsufficientFunds},
+	{"exceeds available", ErrInsufficientFunds},
+	{"minimum", ErrBelowMinimum},
+	{"nonce", ErrInvalidNonce},
+	{"signature", ErrInvalidSignature},
+	{"order not found", ErrOrderNotFound},
+	{"too many requests", ErrRateLimited},
+}
+
+/*
+APIError is an error returned by the API.
+
+Code is the code of the API, like "101" for the v2 API or "0201" for
+the v3 API. Status is the HTTP status of the response and Body the
+raw response, Endpoint is the path requested.
+*/
+type APIError struct {
+	Code     string
+	Message  string
+	Status   int
+	Endpoint string
+	Body     []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (code: %s)", e.Message, e.Code)
+}
+
+// Is reports whether target is one of the sentinel errors
+// matching e, by its code or else by its message.
+func (e *APIError) Is(target error) bool {
+	if e.Status == http.StatusTooManyRequests && target == ErrRateLimited {
+		return true
+	}
+	if errs, ok := errorCodes[e.Code]; ok {
+		for _, err := range errs {
+			if err == target {
+				return true
+			}
+		}
+		return false
+	}
+	message := strings.ToLower(e.Message)
+	for _, m := range errorMessages {
+		if m.err == target && strings.Contains(message, m.substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// As converts e to an *Error, the type of the errors of the
+// v2 responses before they were returned as *APIError.
+func (e *APIError) As(target interface{}) bool {
+	t, ok := target.(**Error)
+	if !ok {
+		return false
+	}
+	code, _ := strconv.Atoi(e.Code)
+	*t = &Error{Code: code, Message: e.Message, Status: e.Status}
+	return true
+}
+
+/*
+HTTPError is returned for the responses that can't be decoded: the
+ones with a 4xx or 5xx status without an error of the API, and the
+ones that aren't JSON regardless of their status, like the HTML pages
+of proxies. Body is truncated to its first bytes.
+*/
+type HTTPError struct {
+	Status   int
+	Endpoint string
+	Body     []byte
+	NotJSON  bool
+}
+
+func (e *HTTPError) Error() string {
+	if e.NotJSON {
+		return fmt.Sprintf("%v (status: %d): %s", ErrNotJSON, e.Status, e.Body)
+	}
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Body)
+}
+
+// Is reports whether target is ErrNotJSON for the responses that
+// aren't JSON or ErrRateLimited for the too many requests status.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotJSON:
+		return e.NotJSON
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// responseError returns the error of a response, nil when it's a
+// JSON or empty response with a successful status. Errors of the API
+// are returned as *APIError, any other failure as *HTTPError.
+func responseError(status int, body []byte) error {
+	if len(bytes.TrimSpace(body)) > 0 && !json.Valid(body) {
+		return &HTTPError{Status: status, Body: truncate(body), NotJSON: true}
+	}
+	if status < http.StatusBadRequest {
+		return nil
+	}
+	e := &envelope{}
+	if json.Unmarshal(body, e) == nil && e.Error != nil {
+		return &APIError{Code: e.Error.Code, Message: e.Error.Message, Status: status, Body: body}
+	}
+	f := &fields{}
+	if json.Unmarshal(body, f) == nil {
+		var apiErr *APIError
+		if errors.As(f.getError(), &apiErr) {
+			apiErr.Status = status
+			apiErr.Body = body
+			return apiErr
+		}
+	}
+	return &HTTPError{Status: status, Body: truncate(body)}
+}
+
+func truncate(body []byte) []byte {
+	if len(body) > maxErrorBody {
+		return body[:maxErrorBody]
+	}
+	return body
+}
+
+// withResponse sets the endpoint of err and the body of
+// the response when it's an *APIError or *HTTPError.
+func withResponse(err error, endpoint string, body []byte) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Endpoint = endpoint
+		if apiErr.Body == nil {
+			apiErr.Body = body
+		}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		httpErr.Endpoint = endpoint
+	}
+	return err
+}
@@ -0,0 +1,189 @@
+package bitso
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAPIError(t *testing.T) {
+	Convey("Given an error of the v2 API", t, func() {
+		err := error(&APIError{Code: "101", Message: "Invalid API Code or Invalid Signature: "})
+
+		Convey("The message should keep the format of the API", func() {
+			So(err.Error(), ShouldEqual, "Invalid API Code or Invalid Signature:  (code: 101)")
+		})
+
+		Convey("It should match its sentinel", func() {
+			So(errors.Is(err, ErrInvalidSignature), ShouldBeTrue)
+			So(errors.Is(err, ErrRateLimited), ShouldBeFalse)
+		})
+
+		Convey("When it's wrapped", func() {
+			wrapped := fmt.Errorf("balance: %w", err)
+
+			Convey("It should still be inspectable", func() {
+				var apiErr *APIError
+				So(errors.As(wrapped, &apiErr), ShouldBeTrue)
+				So(apiErr.Code, ShouldEqual, "101")
+				So(errors.Is(wrapped, ErrInvalidSignature), ShouldBeTrue)
+			})
+
+			Convey("It should still be an *Error", func() {
+				var e *Error
+				So(errors.As(wrapped, &e), ShouldBeTrue)
+				So(e.Code, ShouldEqual, 101)
+				So(e.Message, ShouldEqual, "Invalid API Code or Invalid Signature: ")
+			})
+		})
+	})
+
+	Convey("Given the documented codes of the v3 API", t, func() {
+		Convey("They should match their sentinels", func() {
+			So(errors.Is(&APIError{Code: "0305", Message: "Incorrect major, below the minimum"}, ErrBelowMinimum), ShouldBeTrue)
+			So(errors.Is(&APIError{Code: "0404", Message: "Not found"}, ErrOrderNotFound), ShouldBeTrue)
+			So(errors.Is(&APIError{Code: "0205"}, ErrInvalidSignature), ShouldBeTrue)
+		})
+
+		Convey("Their message shouldn't override the code", func() {
+			err := &APIError{Code: "0379", Message: "Insufficient funds, below the minimum of the book"}
+			So(errors.Is(err, ErrInsufficientFunds), ShouldBeTrue)
+			So(errors.Is(err, ErrBelowMinimum), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an error with a code shared by unrelated errors", t, func() {
+		err := &APIError{Code: "4", Message: "Minimum order amount is 0.00001 BTC"}
+
+		Convey("It should be classified by its message", func() {
+			So(errors.Is(err, ErrBelowMinimum), ShouldBeTrue)
+			So(errors.Is(err, ErrInsufficientFunds), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a response with the too many requests status", t, func() {
+		err := &APIError{Status: http.StatusTooManyRequests}
+
+		Convey("It should be ErrRateLimited", func() {
+			So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an order rejected for insufficient funds", t, func() {
+		err := orderError(&APIError{Code: "0379", Message: "Insufficient funds"})
+
+		Convey("The sentinel should be reachable through the *OrderError", func() {
+			So(err, ShouldHaveSameTypeAs, &OrderError{})
+			So(errors.Is(err, ErrInsufficientFunds), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an error that didn't come from the API", t, func() {
+		err := errors.New("connection reset")
+
+		Convey("The response shouldn't be attached", func() {
+			So(withResponse(err, balancePath, []byte("{}")), ShouldEqual, err)
+			So(withResponse(nil, balancePath, nil), ShouldBeNil)
+		})
+	})
+}
+
+func TestHTTPError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a client using the v2 API", t, func() {
+		client := NewClient()
+		client.Retry = &RetryPolicy{MaxAttempts: 1}
+		registerBooksResponder()
+
+		Convey("When a proxy answers with an HTML page", func() {
+			page := "<html>" + strings.Repeat("Service Unavailable ", 100) + "</html>"
+			httpmock.RegisterResponder("GET", URL+tickerPath,
+				httpmock.NewStringResponder(http.StatusServiceUnavailable, page))
+			_, err := client.Ticker(BTCMXN)
+
+			Convey("err should be an *HTTPError with the status and a truncated body", func() {
+				var httpErr *HTTPError
+				So(errors.As(err, &httpErr), ShouldBeTrue)
+				So(httpErr.Status, ShouldEqual, http.StatusServiceUnavailable)
+				So(httpErr.Endpoint, ShouldEqual, tickerPath)
+				So(httpErr.Body, ShouldHaveLength, maxErrorBody)
+				So(errors.Is(err, ErrNotJSON), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a successful response isn't JSON", func() {
+			httpmock.RegisterResponder("GET", URL+tickerPath,
+				httpmock.NewStringResponder(http.StatusOK, "OK"))
+			_, err := client.Ticker(BTCMXN)
+
+			Convey("err should be ErrNotJSON", func() {
+				So(errors.Is(err, ErrNotJSON), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the server fails with a JSON body that isn't an error of the API", func() {
+			httpmock.RegisterResponder("GET", URL+tickerPath,
+				httpmock.NewStringResponder(http.StatusBadGateway, `{"message": "bad gateway"}`))
+			_, err := client.Ticker(BTCMXN)
+
+			Convey("err should carry the status", func() {
+				var httpErr *HTTPError
+				So(errors.As(err, &httpErr), ShouldBeTrue)
+				So(httpErr.Status, ShouldEqual, http.StatusBadGateway)
+				So(httpErr.NotJSON, ShouldBeFalse)
+				So(err.Error(), ShouldEqual, `502 Bad Gateway: {"message": "bad gateway"}`)
+			})
+		})
+
+		Convey("When the requests are rate limited", func() {
+			httpmock.RegisterResponder("GET", URL+tickerPath,
+				httpmock.NewStringResponder(http.StatusTooManyRequests, `{}`))
+			_, err := client.Ticker(BTCMXN)
+
+			Convey("err should be ErrRateLimited", func() {
+				So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an error of the API with a failing status", t, func() {
+		err := responseError(http.StatusUnauthorized, []byte(`{"success": false, "error": {"code": "0201", "message": "Invalid Nonce or Invalid Signature"}}`))
+
+		Convey("It should be an *APIError with the status", func() {
+			var apiErr *APIError
+			So(errors.As(err, &apiErr), ShouldBeTrue)
+			So(apiErr.Code, ShouldEqual, "0201")
+			So(apiErr.Status, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+
+	Convey("Given an empty response", t, func() {
+		Convey("It should not be an error with a successful status", func() {
+			So(responseError(http.StatusNoContent, nil), ShouldBeNil)
+			So(responseError(http.StatusOK, []byte("\n")), ShouldBeNil)
+		})
+
+		Convey("It should not be ErrNotJSON with a failing status", func() {
+			err := responseError(http.StatusServiceUnavailable, nil)
+			var httpErr *HTTPError
+			So(errors.As(err, &httpErr), ShouldBeTrue)
+			So(httpErr.Status, ShouldEqual, http.StatusServiceUnavailable)
+			So(errors.Is(err, ErrNotJSON), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a v2 error with a successful status", t, func() {
+		err := responseError(http.StatusOK, []byte(`{"error": {"code": 101, "message": "Invalid signature"}}`))
+
+		Convey("It should be left to the caller to decode", func() {
+			So(err, ShouldBeNil)
+		})
+	})
+}
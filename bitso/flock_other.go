@@ -0,0 +1,10 @@
+//go:build !unix
+// +build !unix
+
+package bitso
+
+// lockFile doesn't lock on the platforms without flock,
+// only the goroutines sharing a FileNonce are serialized.
+func lockFile(path string) (unlock func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+// +build unix
+
+package bitso
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile holds an exclusive lock on the file in path, creating it,
+// until unlock is called. It blocks while another process holds it.
+func lockFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,161 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+const (
+	bitcoinDepositAddressPath = "bitcoin_deposit_address"
+	fundingDestinationPathV3  = "funding_destination/"
+	fundingsPathV3            = "fundings/"
+)
+
+// FundingDestination is where funds of a currency must be sent
+// to be deposited into the account. Name describes the kind of
+// Identifier, like a bitcoin address or a CLABE.
+type FundingDestination struct {
+	Name       string `json:"account_identifier_name"`
+	Identifier string `json:"account_identifier"`
+}
+
+// Funding is a deposit of funds into the account.
+type Funding struct {
+	Id        string          `json:"fid"`
+	Status    string          `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	Currency  Currency        `json:"currency"`
+	Method    string          `json:"method"`
+	Amount    Decimal         `json:"amount"`
+	Details   *FundingDetails `json:"details"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (f *Funding) UnmarshalJSON(data []byte) error {
+	type funding Funding
+	v := &struct {
+		*funding
+		CreatedAt timestamp `json:"created_at"`
+	}{funding: (*funding)(f)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	f.CreatedAt = v.CreatedAt.Time()
+	return nil
+}
+
+// FundingDetails holds the origin of a funding,
+// only the fields matching its method are set.
+type FundingDetails struct {
+	TxHash       string `json:"tx_hash,omitempty"`
+	SenderName   string `json:"sender_name,omitempty"`
+	SenderBank   string `json:"sender_bank,omitempty"`
+	SenderCLABE  string `json:"sender_clabe,omitempty"`
+	ReceiveCLABE string `json:"receive_clabe,omitempty"`
+	NumericRef   string `json:"numeric_reference,omitempty"`
+	NotesRef     string `json:"concepto,omitempty"`
+	TrackingKey  string `json:"clave_rastreo,omitempty"`
+}
+
+/*
+Fundings returns the deposits into the account paginated by page,
+page may be nil.
+
+When ids are given only those fundings are returned and page is
+ignored. It's only available through the v3 API.
+*/
+func (c *Account) Fundings(page *Page, ids ...string) ([]*Funding, error) {
+	return c.FundingsContext(context.Background(), page, ids...)
+}
+
+// FundingsContext is like Fundings with a context.
+func (c *Account) FundingsContext(ctx context.Context, page *Page, ids ...string) ([]*Funding, error) {
+	if c.client.Version != V3 {
+		return nil, errV3Only
+	}
+	var fundings []*Funding
+	path, v := historyQuery(fundingsPathV3, page, ids)
+	if err := c.request(ctx, "GET", path, v, nil, &fundings); err != nil {
+		return nil, err
+	}
+	return fundings, nil
+}
+
+// historyQuery returns the path and query listing either
+// ids or the page of a history.
+func historyQuery(path string, page *Page, ids []string) (string, *url.Values) {
+	if len(ids) > 0 {
+		return idsPath(path, ids), nil
+	}
+	v := &url.Values{}
+	page.values(v)
+	return path, v
+}
+
+// FundingDestination returns where deposits of currency must be sent.
+// It's only available through the v3 API.
+func (c *Account) FundingDestination(currency Currency) (*FundingDestination, error) {
+	return c.FundingDestinationContext(context.Background(), currency)
+}
+
+// FundingDestinationContext is like FundingDestination with a context.
+func (c *Account) FundingDestinationContext(ctx context.Context, currency Currency) (*FundingDestination, error) {
+	if c.client.Version != V3 {
+		return nil, errV3Only
+	}
+	v := &url.Values{}
+	v.Set("fund_currency", currency.String())
+	destination := &FundingDestination{}
+	if err := c.request(ctx, "GET", fundingDestinationPathV3, v, nil, destination); err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+// DepositAddressBTC returns the bitcoin address of the account.
+func (c *Account) DepositAddressBTC() (string, error) {
+	return c.DepositAddressBTCContext(context.Background())
+}
+
+// DepositAddressBTCContext is like DepositAddressBTC with a context.
+func (c *Account) DepositAddressBTCContext(ctx context.Context) (string, error) {
+	if c.client.Version == V3 {
+		return c.fundingIdentifier(ctx, BTC)
+	}
+	var address string
+	if err := c.post(ctx, bitcoinDepositAddressPath, &request{}, &address); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// DepositAddressETH returns the ether address of the account.
+func (c *Account) DepositAddressETH() (string, error) {
+	return c.DepositAddressETHContext(context.Background())
+}
+
+// DepositAddressETHContext is like DepositAddressETH with a context.
+func (c *Account) DepositAddressETHContext(ctx context.Context) (string, error) {
+	return c.fundingIdentifier(ctx, ETH)
+}
+
+// DepositCLABE returns the CLABE receiving the SPEI
+// deposits of the account.
+func (c *Account) DepositCLABE() (string, error) {
+	return c.DepositCLABEContext(context.Background())
+}
+
+// DepositCLABEContext is like DepositCLABE with a context.
+func (c *Account) DepositCLABEContext(ctx context.Context) (string, error) {
+	return c.fundingIdentifier(ctx, MXN)
+}
+
+func (c *Account) fundingIdentifier(ctx context.Context, currency Currency) (string, error) {
+	destination, err := c.FundingDestinationContext(ctx, currency)
+	if err != nil {
+		return "", err
+	}
+	return destination.Identifier, nil
+}
@@ -0,0 +1,207 @@
+package bitso
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFundingDestination(t *testing.T) {
+	httpmock.Activate()
+	registerFundingResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an account using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the funding destination of mxn is requested", func() {
+			destination, err := account.FundingDestination("mxn")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The identifier should be a CLABE", func() {
+				So(destination.Name, ShouldEqual, "CLABE")
+				So(ValidateCLABE(destination.Identifier), ShouldBeNil)
+			})
+		})
+
+		Convey("When the deposit CLABE is requested", func() {
+			clabe, err := account.DepositCLABE()
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The CLABE should be returned", func() {
+				So(clabe, ShouldEqual, "646180157000000004")
+			})
+		})
+
+		Convey("When the bitcoin deposit address is requested", func() {
+			address, err := account.DepositAddressBTC()
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The address should be returned", func() {
+				So(address, ShouldEqual, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy")
+			})
+		})
+
+		Convey("When the ether deposit address is requested", func() {
+			address, err := account.DepositAddressETH()
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The address should be returned", func() {
+				So(address, ShouldEqual, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+			})
+		})
+	})
+
+	Convey("Given an account with fundings", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the fundings are requested", func() {
+			fundings, err := account.Fundings(&Page{Limit: 2})
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Every funding should be returned", func() {
+				So(fundings, ShouldHaveLength, 2)
+				So(fundings[0].Method, ShouldEqual, "sp")
+				So(fundings[0].Details.SenderName, ShouldEqual, "BERTRAND RUSSELL")
+				So(fundings[1].Details.TxHash, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When a funding is requested by id", func() {
+			fundings, err := account.Fundings(nil, "6f1c7d4c0c0a7f8e")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Only that funding should be returned", func() {
+				So(fundings, ShouldHaveLength, 1)
+				So(fundings[0].Status, ShouldEqual, "pending")
+			})
+		})
+	})
+
+	Convey("Given an account using the v2 API", t, func() {
+		account := Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+
+		Convey("When the bitcoin deposit address is requested", func() {
+			address, err := account.DepositAddressBTC()
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The address should be returned", func() {
+				So(address, ShouldEqual, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy")
+			})
+		})
+
+		Convey("When the deposit CLABE is requested", func() {
+			_, err := account.DepositCLABE()
+
+			Convey("err should be errV3Only", func() {
+				So(err, ShouldEqual, errV3Only)
+			})
+		})
+	})
+}
+
+func registerFundingResponder() {
+	destinations := map[string]*FundingDestination{
+		"mxn": {Name: "CLABE", Identifier: "646180157000000004"},
+		"btc": {Name: "Bitcoin address", Identifier: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		"eth": {Name: "Ether address", Identifier: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+	}
+	httpmock.RegisterResponder("GET", URLv3+fundingDestinationPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			destination, ok := destinations[req.URL.Query().Get("fund_currency")]
+			if !ok {
+				return httpmock.NewStringResponse(400, `{"success": false, "error": {"code": "0304", "message": "Invalid currency"}}`), nil
+			}
+			payload, _ := json.Marshal(destination)
+			return v3Response(string(payload))
+		},
+	)
+
+	spei := `{
+		"fid": "c5b8d7f0768ee91d3b33bee648318688",
+		"status": "complete",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"currency": "mxn",
+		"method": "sp",
+		"amount": "300.15",
+		"details": {
+			"sender_name": "BERTRAND RUSSELL",
+			"sender_bank": "BANAMEX",
+			"sender_clabe": "002010077777777771",
+			"receive_clabe": "646180157000000004",
+			"numeric_reference": "80416",
+			"concepto": "Tu pedido",
+			"clave_rastreo": "BNET01001604080002076841"
+		}
+	}`
+	btc := `{
+		"fid": "6f1c7d4c0c0a7f8e",
+		"status": "pending",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"currency": "btc",
+		"method": "btc",
+		"amount": "0.48650929",
+		"details": {"tx_hash": "a1b2c3"}
+	}`
+	httpmock.RegisterResponder("GET", URLv3+fundingsPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response("[" + spei + "," + btc + "]")
+		},
+	)
+	httpmock.RegisterResponder("GET", URLv3+fundingsPathV3+"6f1c7d4c0c0a7f8e/",
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response("[" + btc + "]")
+		},
+	)
+
+	httpmock.RegisterResponder("POST", URL+bitcoinDepositAddressPath,
+		func(req *http.Request) (*http.Response, error) {
+			r := &request{}
+			if err := json.NewDecoder(req.Body).Decode(r); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			if r.Key != "key" {
+				f := fields{Error: Error{Code: 101, Message: "Invalid API Code or Invalid Signature: " + r.Key}}
+				return httpmock.NewJsonResponse(200, f)
+			}
+			return httpmock.NewJsonResponse(200, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy")
+		},
+	)
+}
@@ -0,0 +1,135 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const ledgerPathV3 = "ledger/"
+
+// Operation is the type of a ledger entry.
+type Operation string
+
+const (
+	OperationTrade      Operation = "trade"
+	OperationFee        Operation = "fee"
+	OperationFunding    Operation = "funding"
+	OperationWithdrawal Operation = "withdrawal"
+)
+
+var errInvalidOperation = errors.New("Invalid operation value")
+
+// ledgerPaths maps every operation to the endpoint listing
+// only the entries of that operation.
+var ledgerPaths = map[Operation]string{
+	OperationTrade:      ledgerPathV3 + "trades/",
+	OperationFee:        ledgerPathV3 + "fees/",
+	OperationFunding:    ledgerPathV3 + "fundings/",
+	OperationWithdrawal: ledgerPathV3 + "withdrawals/",
+}
+
+// LedgerEntry is a single operation of the account ledger.
+// Every currency affected by the operation has its own
+// BalanceUpdate leg.
+type LedgerEntry struct {
+	Id             string           `json:"eid"`
+	Operation      Operation        `json:"operation"`
+	CreatedAt      time.Time        `json:"created_at"`
+	BalanceUpdates []*BalanceUpdate `json:"balance_updates"`
+	Details        *LedgerDetails   `json:"details"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (e *LedgerEntry) UnmarshalJSON(data []byte) error {
+	type ledgerEntry LedgerEntry
+	v := &struct {
+		*ledgerEntry
+		CreatedAt timestamp `json:"created_at"`
+	}{ledgerEntry: (*ledgerEntry)(e)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	e.CreatedAt = v.CreatedAt.Time()
+	return nil
+}
+
+// BalanceUpdate is the change of the balance of a currency.
+// Negative amounts are debits.
+type BalanceUpdate struct {
+	Currency Currency `json:"currency"`
+	Amount   Decimal  `json:"amount"`
+}
+
+// LedgerDetails references the origin of a ledger entry, only
+// the fields matching the entry operation are set.
+type LedgerDetails struct {
+	Tid    int64  `json:"tid,omitempty"`
+	Oid    string `json:"oid,omitempty"`
+	Fid    string `json:"fid,omitempty"`
+	Wid    string `json:"wid,omitempty"`
+	Method string `json:"method,omitempty"`
+}
+
+// Update returns the leg of the entry for currency,
+// or nil if the currency wasn't affected.
+func (e *LedgerEntry) Update(currency Currency) *BalanceUpdate {
+	for _, u := range e.BalanceUpdates {
+		if u.Currency == currency {
+			return u
+		}
+	}
+	return nil
+}
+
+/*
+Ledger returns the account ledger paginated by page, page may be nil.
+
+A single operation is requested from its own endpoint. With several
+operations the whole ledger is requested and filtered, so fewer than
+page.Limit entries may be returned. The ledger is only available
+through the v3 API.
+*/
+func (c *Account) Ledger(page *Page, operations ...Operation) ([]*LedgerEntry, error) {
+	return c.LedgerContext(context.Background(), page, operations...)
+}
+
+// LedgerContext is like Ledger with a context.
+func (c *Account) LedgerContext(ctx context.Context, page *Page, operations ...Operation) ([]*LedgerEntry, error) {
+	if c.client.Version != V3 {
+		return nil, errV3Only
+	}
+	path := ledgerPathV3
+	if len(operations) == 1 {
+		p, ok := ledgerPaths[operations[0]]
+		if !ok {
+			return nil, errInvalidOperation
+		}
+		path = p
+	}
+	filter := make(map[Operation]bool, len(operations))
+	for _, op := range operations {
+		if _, ok := ledgerPaths[op]; !ok {
+			return nil, errInvalidOperation
+		}
+		filter[op] = true
+	}
+	v := &url.Values{}
+	page.values(v)
+	var entries []*LedgerEntry
+	if err := c.request(ctx, "GET", path, v, nil, &entries); err != nil {
+		return nil, err
+	}
+	if len(operations) < 2 {
+		return entries, nil
+	}
+	filtered := entries[:0]
+	for _, e := range entries {
+		if filter[e.Operation] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
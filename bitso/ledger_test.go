@@ -0,0 +1,131 @@
+package bitso
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLedger(t *testing.T) {
+	httpmock.Activate()
+	registerLedgerResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an account using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the whole ledger is requested", func() {
+			entries, err := account.Ledger(nil)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Every operation should be returned", func() {
+				So(entries, ShouldHaveLength, 3)
+			})
+
+			Convey("The trade should have a leg per currency", func() {
+				trade := entries[0]
+				So(trade.Operation, ShouldEqual, OperationTrade)
+				So(trade.Update("btc").Amount.String(), ShouldEqual, "-0.25232073")
+				So(trade.Update("mxn").Amount.String(), ShouldEqual, "1013.540958479115")
+				So(trade.Update("eth"), ShouldBeNil)
+				So(trade.Details.Oid, ShouldEqual, "19vaqiv72drbphig")
+			})
+
+			Convey("The funding should have its details", func() {
+				So(entries[2].Details.Fid, ShouldEqual, "6112c6369100d6ecceb7f54f17cf0511")
+				So(entries[2].Details.Method, ShouldEqual, "btc")
+			})
+		})
+
+		Convey("When a single operation is requested", func() {
+			entries, err := account.Ledger(&Page{Limit: 5}, OperationFee)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Its own endpoint should be used", func() {
+				So(entries, ShouldHaveLength, 1)
+				So(entries[0].Operation, ShouldEqual, OperationFee)
+			})
+		})
+
+		Convey("When several operations are requested", func() {
+			entries, err := account.Ledger(nil, OperationFee, OperationFunding)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The other operations should be filtered", func() {
+				So(entries, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When an invalid operation is requested", func() {
+			_, err := account.Ledger(nil, Operation("invalid"))
+
+			Convey("err should be errInvalidOperation", func() {
+				So(err, ShouldEqual, errInvalidOperation)
+			})
+		})
+	})
+
+	Convey("Given an account using the v2 API", t, func() {
+		account := Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the ledger is requested", func() {
+			_, err := account.Ledger(nil)
+
+			Convey("err should be errV3Only", func() {
+				So(err, ShouldEqual, errV3Only)
+			})
+		})
+	})
+}
+
+func registerLedgerResponder() {
+	trade := `{
+		"eid": "c4ca4238a0b923820dcc509a6f75849b",
+		"operation": "trade",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"balance_updates": [
+			{"currency": "btc", "amount": "-0.25232073"},
+			{"currency": "mxn", "amount": "1013.540958479115"}
+		],
+		"details": {"tid": 51756, "oid": "19vaqiv72drbphig"}
+	}`
+	fee := `{
+		"eid": "6512bd43d9caa6e02c990b0a82652dca",
+		"operation": "fee",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"balance_updates": [{"currency": "mxn", "amount": "-10.134500"}],
+		"details": {"tid": 51756, "oid": "19vaqiv72drbphig"}
+	}`
+	funding := `{
+		"eid": "d9caa6e02c990b0a82652dca6512bd43",
+		"operation": "funding",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"balance_updates": [{"currency": "btc", "amount": "0.48650929"}],
+		"details": {"fid": "6112c6369100d6ecceb7f54f17cf0511", "method": "btc"}
+	}`
+	responder := func(payload string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response(payload)
+		}
+	}
+	httpmock.RegisterResponder("GET", URLv3+ledgerPathV3,
+		responder("["+trade+","+fee+","+funding+"]"))
+	httpmock.RegisterResponder("GET", URLv3+ledgerPaths[OperationFee],
+		responder("["+fee+"]"))
+}
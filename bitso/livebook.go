@@ -0,0 +1,314 @@
+package bitso
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// resyncBackoff paces the snapshots fetched while a LiveBook is
+// out of sync, it's a variable so the tests can resync faster.
+var resyncBackoff = &RetryPolicy{
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+	Jitter:     0.5,
+}
+
+type bookOrder struct {
+	side   string
+	price  Decimal
+	amount Decimal
+}
+
+/*
+LiveBook is an order book maintained locally from a REST snapshot
+and the diff-orders channel of the websocket feed.
+
+Diffs are applied by sequence number. Whenever one is missing the
+book keeps the last state applied and buffers the following diffs,
+fetching new snapshots with an increasing backoff until one catches
+up with them. Every read method is safe for concurrent use and sees
+the book as of a single sequence.
+*/
+type LiveBook struct {
+	book     Book
+	snapshot func(context.Context) (*orderBookV3, error)
+	diffs    <-chan *DiffOrdersMessage
+	stream   *Stream
+
+	mutex    sync.RWMutex
+	orders   map[string]*bookOrder
+	sequence int64
+
+	pending []*DiffOrdersMessage
+	err     error
+	done    chan struct{}
+}
+
+type snapshotResult struct {
+	snapshot *orderBookV3
+	err      error
+}
+
+// LiveBook starts maintaining the order book of book. It's only
+// available through the v3 API, which provides sequenced snapshots.
+func (c *Client) LiveBook(book Book) (*LiveBook, error) {
+	return c.LiveBookContext(context.Background(), book)
+}
+
+// LiveBookContext is like LiveBook with a context, which only
+// bounds the setup. The book is maintained until it's closed.
+func (c *Client) LiveBookContext(ctx context.Context, book Book) (*LiveBook, error) {
+	if c.Version != V3 {
+		return nil, errV3Only
+	}
+	if err := c.validateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	stream, err := c.DialStreamContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SubscribeContext(ctx, book, ChannelDiffOrders); err != nil {
+		stream.Close()
+		return nil, err
+	}
+	snapshot := func(ctx context.Context) (*orderBookV3, error) {
+		return c.orderBookOrdersV3(ctx, book)
+	}
+	b, err := newLiveBook(ctx, book, snapshot, stream.DiffOrders(), stream)
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// newLiveBook fetches the first snapshot and starts applying diffs,
+// stream is closed by the book and may be nil.
+func newLiveBook(ctx context.Context, book Book, snapshot func(context.Context) (*orderBookV3, error), diffs <-chan *DiffOrdersMessage, stream *Stream) (*LiveBook, error) {
+	b := &LiveBook{
+		book:     book,
+		snapshot: snapshot,
+		diffs:    diffs,
+		stream:   stream,
+		done:     make(chan struct{}),
+	}
+	if err := b.resync(ctx); err != nil {
+		return nil, err
+	}
+	go b.run()
+	return b, nil
+}
+
+// Book returns the book maintained.
+func (b *LiveBook) Book() Book {
+	return b.book
+}
+
+// Sequence returns the sequence of the last diff applied.
+func (b *LiveBook) Sequence() int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.sequence
+}
+
+// BestBid returns the highest bid, ok is false when there are no bids.
+func (b *LiveBook) BestBid() (level *PriceLevel, ok bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	bids := b.levels(Buy)
+	if len(bids) == 0 {
+		return nil, false
+	}
+	return bids[0], true
+}
+
+// BestAsk returns the lowest ask, ok is false when there are no asks.
+func (b *LiveBook) BestAsk() (level *PriceLevel, ok bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	asks := b.levels(Sell)
+	if len(asks) == 0 {
+		return nil, false
+	}
+	return asks[0], true
+}
+
+// OrderBook returns a copy of the book aggregated by price,
+// with the best prices first.
+func (b *LiveBook) OrderBook() *OrderBookInfo {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return &OrderBookInfo{
+		Bids:     b.levels(Buy),
+		Asks:     b.levels(Sell),
+		Sequence: b.sequence,
+	}
+}
+
+// Done is closed when the book stops being maintained.
+func (b *LiveBook) Done() <-chan struct{} {
+	return b.done
+}
+
+// Err returns the reason the book stopped being
+// maintained, it's only set once Done is closed.
+func (b *LiveBook) Err() error {
+	select {
+	case <-b.done:
+		return b.err
+	default:
+		return nil
+	}
+}
+
+// Close stops maintaining the book.
+func (b *LiveBook) Close() error {
+	if b.stream == nil {
+		return nil
+	}
+	return b.stream.Close()
+}
+
+// levels aggregates the orders of side by price, the
+// best price first. The caller must hold the mutex.
+func (b *LiveBook) levels(side string) []*PriceLevel {
+	var levels []*PriceLevel
+	for _, o := range b.orders {
+		if o.side == side {
+			levels = append(levels, &PriceLevel{Price: o.price, Amount: o.amount})
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		cmp := levels[i].Price.Cmp(levels[j].Price)
+		if side == Buy {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	// Orders at the same price are next to each other once sorted.
+	aggregated := levels[:0]
+	for _, l := range levels {
+		if n := len(aggregated); n > 0 && aggregated[n-1].Price.Equal(l.Price) {
+			aggregated[n-1].Amount = aggregated[n-1].Amount.Add(l.Amount)
+			continue
+		}
+		aggregated = append(aggregated, l)
+	}
+	return aggregated
+}
+
+// run applies the diffs until they end. Snapshots are fetched in the
+// background, so the diffs keep being buffered while the book resyncs.
+func (b *LiveBook) run() {
+	defer close(b.done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		wait      <-chan time.Time
+		snapshots chan snapshotResult
+		resyncs   int
+	)
+	for {
+		select {
+		case m, ok := <-b.diffs:
+			if !ok {
+				b.err = errStreamClosed
+				if b.stream != nil && b.stream.Err() != nil {
+					b.err = b.stream.Err()
+				}
+				return
+			}
+			if m.Book == b.book {
+				b.pending = append(b.pending, m)
+			}
+		case <-wait:
+			wait = nil
+			snapshots = make(chan snapshotResult, 1)
+			go func(results chan<- snapshotResult) {
+				snapshot, err := b.snapshot(ctx)
+				results <- snapshotResult{snapshot: snapshot, err: err}
+			}(snapshots)
+		case r := <-snapshots:
+			snapshots = nil
+			// A snapshot that can't be fetched is retried like one
+			// that's behind the diffs, an older one than the last
+			// state applied is discarded.
+			if r.err == nil {
+				b.mutex.Lock()
+				if r.snapshot.Sequence >= b.sequence {
+					b.reset(r.snapshot)
+				}
+				b.mutex.Unlock()
+			}
+		}
+		if wait != nil || snapshots != nil {
+			continue
+		}
+		if b.apply() {
+			resyncs = 0
+			continue
+		}
+		resyncs++
+		wait = time.After(resyncBackoff.backoff(resyncs))
+	}
+}
+
+// apply consumes the pending diffs in sequence, it returns false
+// when one is missing, leaving the following ones pending.
+func (b *LiveBook) apply() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for len(b.pending) > 0 {
+		m := b.pending[0]
+		if m.Sequence > b.sequence+1 {
+			return false
+		}
+		// The older diffs are already part of the snapshot.
+		if m.Sequence == b.sequence+1 {
+			b.applyDiff(m)
+		}
+		b.pending = b.pending[1:]
+	}
+	return true
+}
+
+// applyDiff updates the orders with m. The caller must hold the mutex.
+func (b *LiveBook) applyDiff(m *DiffOrdersMessage) {
+	for _, o := range m.Orders {
+		if o.Status != "open" || o.Amount.IsZero() {
+			delete(b.orders, o.OrderId)
+			continue
+		}
+		b.orders[o.OrderId] = &bookOrder{side: o.Side, price: o.Price, amount: o.Amount}
+	}
+	b.sequence = m.Sequence
+}
+
+// resync replaces the orders with a new snapshot.
+func (b *LiveBook) resync(ctx context.Context) error {
+	snapshot, err := b.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	b.reset(snapshot)
+	b.mutex.Unlock()
+	return nil
+}
+
+// reset replaces the orders with snapshot. The caller must hold the mutex.
+func (b *LiveBook) reset(snapshot *orderBookV3) {
+	orders := map[string]*bookOrder{}
+	add := func(side string, entries []*orderBookEntryV3) {
+		for _, e := range entries {
+			orders[e.Oid] = &bookOrder{side: side, price: e.Price, amount: e.Amount}
+		}
+	}
+	add(Buy, snapshot.Bids)
+	add(Sell, snapshot.Asks)
+	b.orders = orders
+	b.sequence = snapshot.Sequence
+}
@@ -0,0 +1,291 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func init() {
+	resyncBackoff = &RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+}
+
+// waitUntil polls cond for up to a second.
+func waitUntil(cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// fakeSnapshots returns the snapshots in order, repeating the last one.
+func fakeSnapshots(snapshots ...*orderBookV3) (func(context.Context) (*orderBookV3, error), *int) {
+	var mutex sync.Mutex
+	calls := 0
+	return func(context.Context) (*orderBookV3, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		i := calls
+		if i >= len(snapshots) {
+			i = len(snapshots) - 1
+		}
+		calls++
+		return snapshots[i], nil
+	}, &calls
+}
+
+func diff(sequence int64, orders ...*StreamOrder) *DiffOrdersMessage {
+	return &DiffOrdersMessage{Book: BTCMXN, Sequence: sequence, Orders: orders}
+}
+
+// waitFor returns once the diffs sent before it are applied, the
+// book only receives the message of another book after that.
+func waitFor(diffs chan *DiffOrdersMessage) {
+	diffs <- &DiffOrdersMessage{Book: ETHMXN}
+}
+
+func TestLiveBook(t *testing.T) {
+	Convey("Given a live book built from a snapshot", t, func() {
+		snapshot := &orderBookV3{
+			Sequence: 10,
+			Bids: []*orderBookEntryV3{
+				{Price: MustParseDecimal("5600.00"), Amount: MustParseDecimal("0.5"), Oid: "b1"},
+				{Price: MustParseDecimal("5600.00"), Amount: MustParseDecimal("0.25"), Oid: "b2"},
+				{Price: MustParseDecimal("5500.00"), Amount: MustParseDecimal("1"), Oid: "b3"},
+			},
+			Asks: []*orderBookEntryV3{
+				{Price: MustParseDecimal("5700.00"), Amount: MustParseDecimal("2"), Oid: "a1"},
+			},
+		}
+		resynced := &orderBookV3{
+			Sequence: 20,
+			Bids:     []*orderBookEntryV3{{Price: MustParseDecimal("5650.00"), Amount: MustParseDecimal("3"), Oid: "b4"}},
+			Asks:     []*orderBookEntryV3{{Price: MustParseDecimal("5660.00"), Amount: MustParseDecimal("4"), Oid: "a2"}},
+		}
+		snapshots, calls := fakeSnapshots(snapshot, resynced)
+		diffs := make(chan *DiffOrdersMessage)
+		book, err := newLiveBook(context.Background(), BTCMXN, snapshots, diffs, nil)
+		So(err, ShouldBeNil)
+
+		Convey("The best bid should aggregate the orders at its price", func() {
+			bid, ok := book.BestBid()
+			So(ok, ShouldBeTrue)
+			So(bid.Price.String(), ShouldEqual, "5600.00")
+			So(bid.Amount.String(), ShouldEqual, "0.75")
+		})
+
+		Convey("The best ask should be the lowest", func() {
+			ask, ok := book.BestAsk()
+			So(ok, ShouldBeTrue)
+			So(ask.Price.String(), ShouldEqual, "5700.00")
+		})
+
+		Convey("When the next diff is received", func() {
+			diffs <- diff(11,
+				&StreamOrder{OrderId: "b1", Side: Buy, Price: MustParseDecimal("5600.00"), Status: "cancelled"},
+				&StreamOrder{OrderId: "a3", Side: Sell, Price: MustParseDecimal("5650.00"), Amount: MustParseDecimal("1"), Status: "open"},
+			)
+			waitFor(diffs)
+
+			Convey("It should be applied", func() {
+				So(book.Sequence(), ShouldEqual, 11)
+				bid, _ := book.BestBid()
+				So(bid.Amount.String(), ShouldEqual, "0.25")
+				ask, _ := book.BestAsk()
+				So(ask.Price.String(), ShouldEqual, "5650.00")
+			})
+		})
+
+		Convey("When an old diff is received", func() {
+			diffs <- diff(9, &StreamOrder{OrderId: "b1", Side: Buy, Status: "cancelled"})
+			waitFor(diffs)
+
+			Convey("It should be ignored", func() {
+				So(book.Sequence(), ShouldEqual, 10)
+				bid, _ := book.BestBid()
+				So(bid.Amount.String(), ShouldEqual, "0.75")
+			})
+		})
+
+		Convey("When a diff is missing", func() {
+			diffs <- diff(12, &StreamOrder{OrderId: "b5", Side: Buy, Price: MustParseDecimal("1"), Amount: MustParseDecimal("1"), Status: "open"})
+			waitUntil(func() bool { return book.Sequence() == 20 })
+
+			Convey("A new snapshot should be fetched", func() {
+				So(*calls, ShouldEqual, 2)
+				So(book.Sequence(), ShouldEqual, 20)
+				bid, _ := book.BestBid()
+				So(bid.Price.String(), ShouldEqual, "5650.00")
+			})
+		})
+
+		Convey("When the diffs end", func() {
+			close(diffs)
+			<-book.Done()
+
+			Convey("err should be errStreamClosed", func() {
+				So(book.Err(), ShouldEqual, errStreamClosed)
+			})
+		})
+
+		Convey("The aggregated book should be sorted", func() {
+			orderBook := book.OrderBook()
+			So(orderBook.Bids, ShouldHaveLength, 2)
+			So(orderBook.Bids[0].Price.String(), ShouldEqual, "5600.00")
+			So(orderBook.Bids[0].Amount.String(), ShouldEqual, "0.75")
+			So(orderBook.Bids[1].Price.String(), ShouldEqual, "5500.00")
+			So(orderBook.Sequence, ShouldEqual, 10)
+		})
+	})
+
+	Convey("Given snapshots lagging behind the diffs", t, func() {
+		lagging := &orderBookV3{Sequence: 1}
+		snapshots, calls := fakeSnapshots(lagging, lagging, lagging, &orderBookV3{Sequence: 6})
+		diffs := make(chan *DiffOrdersMessage)
+		book, err := newLiveBook(context.Background(), BTCMXN, snapshots, diffs, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When diffs arrive after a gap", func() {
+			diffs <- diff(5)
+			diffs <- diff(7, &StreamOrder{OrderId: "b1", Side: Buy, Price: MustParseDecimal("1"), Amount: MustParseDecimal("1"), Status: "open"})
+			waitUntil(func() bool { return book.Sequence() == 7 })
+
+			Convey("The book should resync until a snapshot catches up with the buffered diffs", func() {
+				So(*calls, ShouldEqual, 4)
+				So(book.Sequence(), ShouldEqual, 7)
+				bid, _ := book.BestBid()
+				So(bid.Price.String(), ShouldEqual, "1")
+				So(book.Err(), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a live book ahead of the snapshots fetched to resync it", t, func() {
+		// The first snapshot builds the book, the rest lag behind it.
+		fetched := make(chan int64, 10)
+		sequence := int64(10)
+		snapshots := func(context.Context) (*orderBookV3, error) {
+			s := sequence
+			sequence = 3
+			fetched <- s
+			return &orderBookV3{Sequence: s}, nil
+		}
+		diffs := make(chan *DiffOrdersMessage)
+		book, err := newLiveBook(context.Background(), BTCMXN, snapshots, diffs, nil)
+		So(err, ShouldBeNil)
+		So(<-fetched, ShouldEqual, 10)
+		diffs <- diff(11, &StreamOrder{OrderId: "b1", Side: Buy, Price: MustParseDecimal("5600.00"), Amount: MustParseDecimal("1"), Status: "open"})
+		waitFor(diffs)
+
+		Convey("When a lagging snapshot arrives after a gap", func() {
+			diffs <- diff(13)
+			// The second resync is only fetched once the first is handled.
+			So(<-fetched, ShouldEqual, 3)
+			So(<-fetched, ShouldEqual, 3)
+
+			Convey("The book should keep the last state applied", func() {
+				So(book.Sequence(), ShouldEqual, 11)
+				bid, ok := book.BestBid()
+				So(ok, ShouldBeTrue)
+				So(bid.Price.String(), ShouldEqual, "5600.00")
+			})
+		})
+	})
+
+	Convey("Given a snapshot that fails once the book is built", t, func() {
+		var mutex sync.Mutex
+		calls := 0
+		snapshots := func(context.Context) (*orderBookV3, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+			if calls == 2 {
+				return nil, errors.New("unavailable")
+			}
+			return &orderBookV3{Sequence: int64(calls) * 10}, nil
+		}
+		diffs := make(chan *DiffOrdersMessage)
+		book, err := newLiveBook(context.Background(), BTCMXN, snapshots, diffs, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When a diff is missing", func() {
+			diffs <- diff(12)
+			waitUntil(func() bool { return book.Sequence() == 30 })
+
+			Convey("The snapshot should be retried", func() {
+				So(book.Sequence(), ShouldEqual, 30)
+				So(book.Err(), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a snapshot that can't be fetched", t, func() {
+		snapshots := func(context.Context) (*orderBookV3, error) {
+			return nil, errors.New("unavailable")
+		}
+
+		Convey("The live book should not be created", func() {
+			_, err := newLiveBook(context.Background(), BTCMXN, snapshots, nil, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestClientLiveBook(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+	server := newStreamServer()
+	defer server.Close()
+	httpmock.RegisterResponder("GET", URLv3+orderBookPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("aggregate") != "false" {
+				return httpmock.NewStringResponse(500, "aggregated"), nil
+			}
+			return v3Response(`{
+				"asks": [{"book": "btc_mxn", "price": "5700.00", "amount": "1", "oid": "a1"}],
+				"bids": [{"book": "btc_mxn", "price": "5500.00", "amount": "1", "oid": "b1"}],
+				"sequence": "2733"
+			}`)
+		},
+	)
+
+	Convey("Given a client using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		client.StreamURL = "ws" + strings.TrimPrefix(server.URL, "http")
+
+		Convey("When a live book is started", func() {
+			book, err := client.LiveBook(BTCMXN)
+			So(err, ShouldBeNil)
+			defer book.Close()
+
+			Convey("The diffs of the feed should be applied to the snapshot", func() {
+				deadline := time.Now().Add(time.Second)
+				for book.Sequence() != 2734 && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+				So(book.Sequence(), ShouldEqual, 2734)
+				bid, _ := book.BestBid()
+				So(bid.Price.String(), ShouldEqual, "5600.00")
+			})
+		})
+	})
+
+	Convey("Given a client using the v2 API", t, func() {
+		Convey("When a live book is started", func() {
+			_, err := NewClient().LiveBook(BTCMXN)
+
+			Convey("err should be errV3Only", func() {
+				So(err, ShouldEqual, errV3Only)
+			})
+		})
+	})
+}
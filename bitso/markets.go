@@ -0,0 +1,170 @@
+package bitso
+
+import (
+	"errors"
+	"strings"
+)
+
+// Currency is the lowercase code of a currency, like "btc".
+type Currency string
+
+const (
+	BTC  Currency = "btc"
+	ETH  Currency = "eth"
+	XRP  Currency = "xrp"
+	LTC  Currency = "ltc"
+	BCH  Currency = "bch"
+	TUSD Currency = "tusd"
+	DAI  Currency = "dai"
+	BAT  Currency = "bat"
+	MANA Currency = "mana"
+	MXN  Currency = "mxn"
+	USD  Currency = "usd"
+	ARS  Currency = "ars"
+	BRL  Currency = "brl"
+)
+
+// defaultPrecision is the precision of the currencies
+// missing from precisions, the one of crypto currencies.
+const defaultPrecision = 8
+
+// precisions are the decimals of the amounts of every currency.
+var precisions = map[Currency]int32{
+	XRP:  6,
+	TUSD: 2,
+	DAI:  2,
+	MXN:  2,
+	USD:  2,
+	ARS:  2,
+	BRL:  2,
+}
+
+// Precision returns the number of decimals of amounts in c.
+func (c Currency) Precision() int32 {
+	if p, ok := precisions[c]; ok {
+		return p
+	}
+	return defaultPrecision
+}
+
+// Round returns d rounded to the precision of c.
+func (c Currency) Round(d Decimal) Decimal {
+	return d.Round(c.Precision())
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Book is a market, the major currency traded and the minor
+// currency it's priced in joined by an underscore, like "btc_mxn".
+type Book string
+
+const (
+	BTCMXN  Book = "btc_mxn"
+	ETHMXN  Book = "eth_mxn"
+	ETHBTC  Book = "eth_btc"
+	XRPMXN  Book = "xrp_mxn"
+	XRPBTC  Book = "xrp_btc"
+	LTCMXN  Book = "ltc_mxn"
+	LTCBTC  Book = "ltc_btc"
+	BCHMXN  Book = "bch_mxn"
+	BCHBTC  Book = "bch_btc"
+	TUSDMXN Book = "tusd_mxn"
+	TUSDBTC Book = "tusd_btc"
+	DAIMXN  Book = "dai_mxn"
+	BTCDAI  Book = "btc_dai"
+	BATMXN  Book = "bat_mxn"
+	BATBTC  Book = "bat_btc"
+	MANAMXN Book = "mana_mxn"
+	MANABTC Book = "mana_btc"
+	BTCUSD  Book = "btc_usd"
+	ETHUSD  Book = "eth_usd"
+	XRPUSD  Book = "xrp_usd"
+	USDMXN  Book = "usd_mxn"
+	BTCARS  Book = "btc_ars"
+	BTCBRL  Book = "btc_brl"
+)
+
+var errMalformedBook = errors.New("Malformed book value")
+
+// knownBooks are the books of the constants, checked by
+// v2 clients when the available books can't be fetched.
+var knownBooks = map[Book]bool{
+	BTCMXN:  true,
+	ETHMXN:  true,
+	ETHBTC:  true,
+	XRPMXN:  true,
+	XRPBTC:  true,
+	LTCMXN:  true,
+	LTCBTC:  true,
+	BCHMXN:  true,
+	BCHBTC:  true,
+	TUSDMXN: true,
+	TUSDBTC: true,
+	DAIMXN:  true,
+	BTCDAI:  true,
+	BATMXN:  true,
+	BATBTC:  true,
+	MANAMXN: true,
+	MANABTC: true,
+	BTCUSD:  true,
+	ETHUSD:  true,
+	XRPUSD:  true,
+	USDMXN:  true,
+	BTCARS:  true,
+	BTCBRL:  true,
+}
+
+// NewBook returns the book trading major priced in minor.
+func NewBook(major, minor Currency) Book {
+	return Book(string(major) + "_" + string(minor))
+}
+
+// ParseBook parses s as a book, like "btc_mxn" or "BTC-MXN".
+// It only checks the format, LookupBook tells whether the book
+// is open for trading.
+func ParseBook(s string) (Book, error) {
+	s = strings.ToLower(strings.Replace(s, "-", "_", 1))
+	parts := strings.Split(s, "_")
+	if len(parts) != 2 || !isCurrencyCode(parts[0]) || !isCurrencyCode(parts[1]) {
+		return "", errMalformedBook
+	}
+	return Book(s), nil
+}
+
+func isCurrencyCode(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Major returns the currency traded in b.
+func (b Book) Major() Currency {
+	major, _ := b.split()
+	return major
+}
+
+// Minor returns the currency prices of b are in.
+func (b Book) Minor() Currency {
+	_, minor := b.split()
+	return minor
+}
+
+func (b Book) split() (Currency, Currency) {
+	i := strings.Index(string(b), "_")
+	if i < 0 {
+		return Currency(b), ""
+	}
+	return Currency(b[:i]), Currency(b[i+1:])
+}
+
+func (b Book) String() string {
+	return string(b)
+}
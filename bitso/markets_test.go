@@ -0,0 +1,53 @@
+package bitso
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBook(t *testing.T) {
+	Convey("Given a book", t, func() {
+		book := BTCMXN
+
+		Convey("Its currencies should be split", func() {
+			So(book.Major(), ShouldEqual, BTC)
+			So(book.Minor(), ShouldEqual, MXN)
+		})
+
+		Convey("It should be built from its currencies", func() {
+			So(NewBook(ETH, BTC), ShouldEqual, ETHBTC)
+		})
+	})
+
+	Convey("Given books to parse", t, func() {
+		Convey("When they are well formed", func() {
+			book, err := ParseBook("XRP-MXN")
+
+			Convey("They should be normalized", func() {
+				So(err, ShouldBeNil)
+				So(book, ShouldEqual, XRPMXN)
+			})
+		})
+
+		Convey("When they are malformed", func() {
+			for _, s := range []string{"", "btc", "btc_", "_mxn", "btc_mxn_usd", "btc mxn"} {
+				_, err := ParseBook(s)
+				So(err, ShouldEqual, errMalformedBook)
+			}
+		})
+	})
+
+	Convey("Given the precision of the currencies", t, func() {
+		Convey("Fiat currencies should have cents", func() {
+			So(MXN.Precision(), ShouldEqual, 2)
+			So(MXN.Round(MustParseDecimal("10.125")).String(), ShouldEqual, "10.13")
+		})
+
+		Convey("Crypto currencies should have their own precision", func() {
+			So(BTC.Precision(), ShouldEqual, 8)
+			So(XRP.Precision(), ShouldEqual, 6)
+			So(Currency("new").Precision(), ShouldEqual, defaultPrecision)
+		})
+	})
+}
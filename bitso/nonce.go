@@ -0,0 +1,141 @@
+package bitso
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NonceSource provides the nonces of the private requests. The API
+// rejects a nonce that isn't greater than the last one of the key.
+type NonceSource interface {
+	Nonce() (int64, error)
+}
+
+// defaultNonceSource is shared by the accounts without a NonceSource,
+// so the accounts of a key in the same process don't repeat nonces.
+var defaultNonceSource = &MonotonicNonce{}
+
+/*
+MonotonicNonce is a NonceSource safe for concurrent use. Nonces are
+the current unix time in nanoseconds, or the last nonce plus one when
+the clock didn't move forward. The zero value is ready to use.
+
+It doesn't survive restarts, FileNonce does.
+*/
+type MonotonicNonce struct {
+	last int64
+}
+
+func (n *MonotonicNonce) Nonce() (int64, error) {
+	for {
+		last := atomic.LoadInt64(&n.last)
+		next := nextNonce(last)
+		if atomic.CompareAndSwapInt64(&n.last, last, next) {
+			return next, nil
+		}
+	}
+}
+
+func nextNonce(last int64) int64 {
+	now := time.Now().UnixNano()
+	if now <= last {
+		return last + 1
+	}
+	return now
+}
+
+/*
+FileNonce is a NonceSource persisting the last nonce in a file, so
+the nonces keep increasing across restarts even if the clock goes
+backwards. It's safe for concurrent use, also by the processes sharing
+the file: every nonce is read, incremented and written holding a lock
+on the file with the ".lock" suffix, except on the platforms without
+flock.
+*/
+type FileNonce struct {
+	path  string
+	mutex sync.Mutex
+	last  int64
+}
+
+// NewFileNonce returns a FileNonce persisting the nonces in path,
+// it continues from the nonce in the file when it exists.
+func NewFileNonce(path string) (*FileNonce, error) {
+	n := &FileNonce{path: path}
+	last, err := readNonce(path)
+	if err != nil {
+		return nil, err
+	}
+	n.last = last
+	return n, nil
+}
+
+// Nonce returns the next nonce once it's persisted. It continues from
+// the nonce in the file, which other processes may have written.
+func (n *FileNonce) Nonce() (int64, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	unlock, err := lockFile(n.path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	last, err := readNonce(n.path)
+	if err != nil {
+		return 0, err
+	}
+	if last < n.last {
+		last = n.last
+	}
+	next := nextNonce(last)
+	if err := n.write(next); err != nil {
+		return 0, err
+	}
+	n.last = next
+	return next, nil
+}
+
+// readNonce returns the nonce in path, 0 when the file doesn't exist
+// or is empty, like after a crash on a filesystem that doesn't order
+// the rename after the data.
+func readNonce(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// write replaces the file through a rename of a synced copy,
+// so a crash never leaves it truncated.
+func (n *FileNonce) write(nonce int64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(n.path), filepath.Base(n.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(strconv.FormatInt(nonce, 10))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), n.path)
+}
@@ -0,0 +1,176 @@
+package bitso
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fixedNonce always returns the same nonce.
+type fixedNonce int64
+
+func (n fixedNonce) Nonce() (int64, error) {
+	return int64(n), nil
+}
+
+func TestNonce(t *testing.T) {
+	Convey("Given a unique nonce", t, func() {
+		nonce, err := defaultNonceSource.Nonce()
+		So(err, ShouldBeNil)
+
+		Convey("When a new nonce is generated", func() {
+			newNonce, err := defaultNonceSource.Nonce()
+			So(err, ShouldBeNil)
+
+			Convey("The new nonce should be greater than the previous one", func() {
+				So(newNonce, ShouldBeGreaterThan, nonce)
+			})
+		})
+	})
+
+	Convey("Given a monotonic nonce ahead of the clock", t, func() {
+		ahead := time.Now().Add(time.Hour).UnixNano()
+		n := &MonotonicNonce{last: ahead}
+
+		Convey("When nonces are generated from several goroutines", func() {
+			var mutex sync.Mutex
+			var wg sync.WaitGroup
+			seen := map[int64]bool{}
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						nonce, _ := n.Nonce()
+						mutex.Lock()
+						seen[nonce] = true
+						mutex.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			Convey("They should keep increasing without repeating", func() {
+				So(seen, ShouldHaveLength, 1000)
+				for nonce := range seen {
+					So(nonce, ShouldBeGreaterThan, ahead)
+				}
+			})
+		})
+	})
+
+	Convey("Given a file nonce", t, func() {
+		dir, err := ioutil.TempDir("", "nonce")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "nonce")
+		ahead := time.Now().Add(time.Hour).UnixNano()
+		So(ioutil.WriteFile(path, []byte(strconv.FormatInt(ahead, 10)+"\n"), 0600), ShouldBeNil)
+		n, err := NewFileNonce(path)
+		So(err, ShouldBeNil)
+
+		Convey("When a nonce is generated", func() {
+			nonce, err := n.Nonce()
+			So(err, ShouldBeNil)
+
+			Convey("It should continue from the persisted one", func() {
+				So(nonce, ShouldEqual, ahead+1)
+			})
+
+			Convey("After a restart the next one should be greater", func() {
+				restarted, err := NewFileNonce(path)
+				So(err, ShouldBeNil)
+				next, err := restarted.Nonce()
+				So(err, ShouldBeNil)
+				So(next, ShouldEqual, nonce+1)
+			})
+		})
+
+		Convey("When the file doesn't exist", func() {
+			n, err := NewFileNonce(filepath.Join(dir, "missing"))
+
+			Convey("It should start from the clock", func() {
+				So(err, ShouldBeNil)
+				nonce, err := n.Nonce()
+				So(err, ShouldBeNil)
+				So(nonce, ShouldBeGreaterThan, 0)
+			})
+		})
+
+		Convey("When another source shares the file", func() {
+			other, err := NewFileNonce(path)
+			So(err, ShouldBeNil)
+			var nonces []int64
+			for i := 0; i < 5; i++ {
+				for _, source := range []NonceSource{n, other} {
+					nonce, err := source.Nonce()
+					So(err, ShouldBeNil)
+					nonces = append(nonces, nonce)
+				}
+			}
+
+			Convey("The nonces of both should keep increasing", func() {
+				for i := 1; i < len(nonces); i++ {
+					So(nonces[i], ShouldBeGreaterThan, nonces[i-1])
+				}
+			})
+		})
+
+		Convey("When the file is empty", func() {
+			So(ioutil.WriteFile(path, nil, 0600), ShouldBeNil)
+			n, err := NewFileNonce(path)
+
+			Convey("It should start from the clock", func() {
+				So(err, ShouldBeNil)
+				nonce, err := n.Nonce()
+				So(err, ShouldBeNil)
+				So(nonce, ShouldBeGreaterThan, 0)
+			})
+		})
+
+		Convey("When the file is corrupted", func() {
+			So(ioutil.WriteFile(path, []byte("nonce"), 0600), ShouldBeNil)
+			_, err := NewFileNonce(path)
+
+			Convey("err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an account with its own nonce source", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+		account.NonceSource = fixedNonce(1234)
+		var authorization string
+		httpmock.RegisterResponder("GET", URLv3+balancePathV3,
+			func(req *http.Request) (*http.Response, error) {
+				authorization = req.Header.Get("Authorization")
+				return v3Response(`{"balances": []}`)
+			},
+		)
+
+		Convey("When the balance is requested", func() {
+			_, err := account.Balance()
+
+			Convey("The request should be signed with its nonce", func() {
+				So(err, ShouldBeNil)
+				So(strings.Split(authorization, ":")[1], ShouldEqual, "1234")
+			})
+		})
+	})
+}
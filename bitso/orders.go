@@ -0,0 +1,322 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	buyPath         = "buy"
+	sellPath        = "sell"
+	cancelOrderPath = "cancel_order"
+	// Buy and Sell are the sides of an order.
+	Buy  = "buy"
+	Sell = "sell"
+	// Limit and Market are the supported order types.
+	Limit  = "limit"
+	Market = "market"
+)
+
+// OrderRejection tells why the exchange rejected an order.
+type OrderRejection int
+
+const (
+	// InsufficientFunds means the balance can't cover the order.
+	InsufficientFunds OrderRejection = iota
+	// BelowMinimum means the amount or value is below the book minimum.
+	BelowMinimum
+)
+
+var (
+	errInvalidSide      = errors.New("Invalid order side")
+	errInvalidOrderType = errors.New("Invalid order type")
+	errMissingPrice     = errors.New("Limit orders require a price")
+	errNotCancelled     = errors.New("Order was not cancelled")
+)
+
+// OrderError is returned when the exchange rejects an order.
+type OrderError struct {
+	Reason OrderRejection
+	Err    *APIError
+}
+
+func (e *OrderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *OrderError) Unwrap() error {
+	return e.Err
+}
+
+type placeOrder struct {
+	fields
+	Book   Book     `json:"book"`
+	Amount Decimal  `json:"amount"`
+	Price  *Decimal `json:"price,omitempty"`
+}
+
+type placeOrderV3 struct {
+	Book  Book     `json:"book"`
+	Side  string   `json:"side"`
+	Type  string   `json:"type"`
+	Major Decimal  `json:"major"`
+	Price *Decimal `json:"price,omitempty"`
+}
+
+// Buy places a limit order to buy amount of the major currency
+// of book at price.
+func (c *Account) Buy(book Book, amount, price Decimal) (*Order, error) {
+	return c.BuyContext(context.Background(), book, amount, price)
+}
+
+// BuyContext is like Buy with a context.
+func (c *Account) BuyContext(ctx context.Context, book Book, amount, price Decimal) (*Order, error) {
+	return c.PlaceOrderContext(ctx, book, Buy, Limit, amount, price)
+}
+
+// Sell places a limit order to sell amount of the major currency
+// of book at price.
+func (c *Account) Sell(book Book, amount, price Decimal) (*Order, error) {
+	return c.SellContext(context.Background(), book, amount, price)
+}
+
+// SellContext is like Sell with a context.
+func (c *Account) SellContext(ctx context.Context, book Book, amount, price Decimal) (*Order, error) {
+	return c.PlaceOrderContext(ctx, book, Sell, Limit, amount, price)
+}
+
+// MarketBuy places a market order to buy amount of the major
+// currency of book.
+func (c *Account) MarketBuy(book Book, amount Decimal) (*Order, error) {
+	return c.MarketBuyContext(context.Background(), book, amount)
+}
+
+// MarketBuyContext is like MarketBuy with a context.
+func (c *Account) MarketBuyContext(ctx context.Context, book Book, amount Decimal) (*Order, error) {
+	return c.PlaceOrderContext(ctx, book, Buy, Market, amount, Decimal{})
+}
+
+// MarketSell places a market order to sell amount of the major
+// currency of book.
+func (c *Account) MarketSell(book Book, amount Decimal) (*Order, error) {
+	return c.MarketSellContext(context.Background(), book, amount)
+}
+
+// MarketSellContext is like MarketSell with a context.
+func (c *Account) MarketSellContext(ctx context.Context, book Book, amount Decimal) (*Order, error) {
+	return c.PlaceOrderContext(ctx, book, Sell, Market, amount, Decimal{})
+}
+
+/*
+PlaceOrder places an order in book and returns it with the id
+assigned by the exchange.
+
+side is Buy or Sell and orderType is Limit or Market. price is
+ignored by market orders. The order is checked against the limits
+of the book before it's sent, rejections of the exchange are
+returned as *OrderError.
+*/
+func (c *Account) PlaceOrder(book Book, side, orderType string, amount, price Decimal) (*Order, error) {
+	return c.PlaceOrderContext(context.Background(), book, side, orderType, amount, price)
+}
+
+// PlaceOrderContext is like PlaceOrder with a context.
+func (c *Account) PlaceOrderContext(ctx context.Context, book Book, side, orderType string, amount, price Decimal) (*Order, error) {
+	info, err := c.client.LookupBookContext(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	if side != Buy && side != Sell {
+		return nil, errInvalidSide
+	}
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+	var limitPrice *Decimal
+	switch orderType {
+	case Market:
+		price = Decimal{}
+	case Limit:
+		if price.Sign() <= 0 {
+			return nil, errMissingPrice
+		}
+		limitPrice = &price
+	default:
+		return nil, errInvalidOrderType
+	}
+	if err := info.ValidateOrder(amount, price); err != nil {
+		return nil, err
+	}
+	var order *Order
+	if c.client.Version == V3 {
+		order, err = c.placeOrderV3(ctx, book, side, orderType, amount, limitPrice)
+	} else {
+		order, err = c.placeOrderV2(ctx, book, side, amount, limitPrice)
+	}
+	if err != nil {
+		return nil, orderError(err)
+	}
+	return order, nil
+}
+
+func (c *Account) placeOrderV2(ctx context.Context, book Book, side string, amount Decimal, price *Decimal) (*Order, error) {
+	path := buyPath
+	if side == Sell {
+		path = sellPath
+	}
+	req := &placeOrder{Book: book, Amount: amount, Price: price}
+	order := &Order{}
+	if err := c.post(ctx, path, req, order); err != nil {
+		return nil, err
+	}
+	order.Side = side
+	return order, nil
+}
+
+func (c *Account) placeOrderV3(ctx context.Context, book Book, side, orderType string, amount Decimal, price *Decimal) (*Order, error) {
+	req := &placeOrderV3{
+		Book:  book,
+		Side:  side,
+		Type:  orderType,
+		Major: amount,
+		Price: price,
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &struct {
+		Oid string `json:"oid"`
+	}{}
+	if err := c.request(ctx, "POST", ordersPathV3, nil, payload, resp); err != nil {
+		return nil, err
+	}
+	order := &Order{
+		Id:             resp.Oid,
+		Book:           book,
+		Side:           side,
+		Type:           orderType,
+		Amount:         amount,
+		OriginalAmount: amount,
+	}
+	if price != nil {
+		order.Price = *price
+	}
+	return order, nil
+}
+
+// CancelResult is the outcome of cancelling a single order.
+type CancelResult struct {
+	Id  string
+	Err error
+}
+
+// CancelOrder cancels the open order with the given id.
+func (c *Account) CancelOrder(id string) error {
+	return c.CancelOrderContext(context.Background(), id)
+}
+
+// CancelOrderContext is like CancelOrder with a context.
+func (c *Account) CancelOrderContext(ctx context.Context, id string) error {
+	results, err := c.CancelOrdersContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return results[0].Err
+}
+
+/*
+CancelOrders cancels the open orders with the given ids and
+returns a result for each one of them, in the same order.
+
+The returned error is only set when the request itself failed,
+orders the exchange refused to cancel have their Err set.
+*/
+func (c *Account) CancelOrders(ids ...string) ([]*CancelResult, error) {
+	return c.CancelOrdersContext(context.Background(), ids...)
+}
+
+// CancelOrdersContext is like CancelOrders with a context.
+func (c *Account) CancelOrdersContext(ctx context.Context, ids ...string) ([]*CancelResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if c.client.Version == V3 {
+		return c.cancelOrdersV3(ctx, ids)
+	}
+	results := make([]*CancelResult, len(ids))
+	for i, id := range ids {
+		results[i] = &CancelResult{Id: id, Err: c.cancelOrderV2(ctx, id)}
+	}
+	return results, nil
+}
+
+// CancelAll cancels every open order in book.
+func (c *Account) CancelAll(book Book) ([]*CancelResult, error) {
+	return c.CancelAllContext(context.Background(), book)
+}
+
+// CancelAllContext is like CancelAll with a context.
+func (c *Account) CancelAllContext(ctx context.Context, book Book) ([]*CancelResult, error) {
+	if err := c.client.validateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	orders, err := c.openOrdersIn(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.Id
+	}
+	return c.CancelOrdersContext(ctx, ids...)
+}
+
+func (c *Account) cancelOrderV2(ctx context.Context, id string) error {
+	var result string
+	order := &orderRequest{Id: id}
+	if err := c.post(ctx, cancelOrderPath, order, &result); err != nil {
+		return err
+	}
+	if result != "true" {
+		return errNotCancelled
+	}
+	return nil
+}
+
+func (c *Account) cancelOrdersV3(ctx context.Context, ids []string) ([]*CancelResult, error) {
+	var cancelled []string
+	path := idsPath(ordersPathV3, ids)
+	if err := c.request(ctx, "DELETE", path, nil, nil, &cancelled); err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(cancelled))
+	for _, id := range cancelled {
+		done[id] = true
+	}
+	results := make([]*CancelResult, len(ids))
+	for i, id := range ids {
+		results[i] = &CancelResult{Id: id}
+		if !done[id] {
+			results[i].Err = errNotCancelled
+		}
+	}
+	return results, nil
+}
+
+// orderError turns the exchange errors that reject an order
+// into an *OrderError, any other error is returned as is.
+func orderError(err error) error {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case errors.Is(e, ErrInsufficientFunds):
+		return &OrderError{Reason: InsufficientFunds, Err: e}
+	case errors.Is(e, ErrBelowMinimum):
+		return &OrderError{Reason: BelowMinimum, Err: e}
+	}
+	return err
+}
@@ -0,0 +1,267 @@
+package bitso
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrders(t *testing.T) {
+	httpmock.Activate()
+	registerResponder()
+	registerResponderV3()
+	registerOrdersResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an account using the v2 API", t, func() {
+		account := Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+
+		Convey("When a limit buy order is placed", func() {
+			order, err := account.Buy(BTCMXN, MustParseDecimal("0.01"), MustParseDecimal("5600.00"))
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The order should have an id", func() {
+				So(order.Id, ShouldEqual, "qlbga6b600n3xta7actori10z19acfb20njbtuhtu5xry7z8jswbaycazlkc0wf1")
+				So(order.Side, ShouldEqual, Buy)
+				So(order.Price.String(), ShouldEqual, "5600.00")
+			})
+		})
+
+		Convey("When a market sell order is placed", func() {
+			order, err := account.MarketSell(BTCMXN, MustParseDecimal("0.01"))
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The price should not be sent", func() {
+				So(order.Price.IsZero(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a limit order is placed without price", func() {
+			_, err := account.Buy(BTCMXN, MustParseDecimal("0.01"), Decimal{})
+
+			Convey("err should be errMissingPrice", func() {
+				So(err, ShouldEqual, errMissingPrice)
+			})
+		})
+
+		Convey("When an order is placed in an invalid book", func() {
+			_, err := account.Sell("invalid_book", MustParseDecimal("0.01"), MustParseDecimal("5600.00"))
+
+			Convey("An error should occur", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the funds can't cover the order", func() {
+			_, err := account.Buy(BTCMXN, MustParseDecimal("1000"), MustParseDecimal("5600.00"))
+
+			Convey("err should be an *OrderError for insufficient funds", func() {
+				orderErr, ok := err.(*OrderError)
+				So(ok, ShouldBeTrue)
+				So(orderErr.Reason, ShouldEqual, InsufficientFunds)
+			})
+		})
+
+		Convey("When the amount is below the book minimum", func() {
+			_, err := account.Buy(BTCMXN, MustParseDecimal("0.00000001"), MustParseDecimal("5600.00"))
+
+			Convey("err should be errAmountOutOfRange", func() {
+				So(err, ShouldEqual, errAmountOutOfRange)
+			})
+		})
+
+		Convey("When the exchange rejects the order for the minimum", func() {
+			_, err := account.Buy(BTCMXN, MustParseDecimal("0.0005"), MustParseDecimal("5600.00"))
+
+			Convey("err should be an *OrderError for the minimum", func() {
+				orderErr, ok := err.(*OrderError)
+				So(ok, ShouldBeTrue)
+				So(orderErr.Reason, ShouldEqual, BelowMinimum)
+			})
+		})
+
+		Convey("When an order is cancelled", func() {
+			err := account.CancelOrder("543cr2v32a1h684430tvcqx1b0vkr93wd694957cg8umhyrlzkgbaedmf976ia3v")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When a list of orders is cancelled", func() {
+			results, err := account.CancelOrders("543cr2v32a1h684430tvcqx1b0vkr93wd694957cg8umhyrlzkgbaedmf976ia3v", "unknown")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("There should be a result for each id", func() {
+				So(results, ShouldHaveLength, 2)
+				So(results[0].Err, ShouldBeNil)
+				So(results[1].Id, ShouldEqual, "unknown")
+				So(results[1].Err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When every order in a book is cancelled", func() {
+			results, err := account.CancelAll(BTCMXN)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Every open order should be cancelled", func() {
+				So(results, ShouldHaveLength, 3)
+				for _, result := range results {
+					So(result.Err, ShouldBeNil)
+				}
+			})
+		})
+	})
+
+	Convey("Given an account using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When a limit sell order is placed", func() {
+			order, err := account.Sell(BTCMXN, MustParseDecimal("0.01"), MustParseDecimal("5600.00"))
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The order should have the oid as id", func() {
+				So(order.Id, ShouldEqual, "qlbga6b600n3xta7")
+				So(order.Type, ShouldEqual, Limit)
+			})
+		})
+
+		Convey("When the funds can't cover the order", func() {
+			_, err := account.MarketBuy(BTCMXN, MustParseDecimal("1000"))
+
+			Convey("err should be an *OrderError for insufficient funds", func() {
+				orderErr, ok := err.(*OrderError)
+				So(ok, ShouldBeTrue)
+				So(orderErr.Reason, ShouldEqual, InsufficientFunds)
+			})
+		})
+
+		Convey("When a list of orders is cancelled", func() {
+			results, err := account.CancelOrders("543cr2v32a1h6844", "unknown")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Only the cancelled ids should succeed", func() {
+				So(results, ShouldHaveLength, 2)
+				So(results[0].Err, ShouldBeNil)
+				So(results[1].Err, ShouldEqual, errNotCancelled)
+			})
+		})
+
+		Convey("When every order in a book is cancelled", func() {
+			results, err := account.CancelAll(BTCMXN)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The open order should be cancelled", func() {
+				So(results, ShouldHaveLength, 1)
+				So(results[0].Err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func registerOrdersResponder() {
+	v2 := func(req *http.Request) (*http.Response, error) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return httpmock.NewStringResponse(500, err.Error()), nil
+		}
+		order := &placeOrder{}
+		if err := json.Unmarshal(body, order); err != nil {
+			return httpmock.NewStringResponse(500, err.Error()), nil
+		}
+		f := fields{}
+		switch order.Amount.String() {
+		case "1000":
+			f.Error = Error{Code: 4, Message: "Insufficient funds"}
+			return httpmock.NewJsonResponse(200, f)
+		case "0.0005":
+			f.Error = Error{Code: 4, Message: "Minimum order amount is 0.00001 BTC"}
+			return httpmock.NewJsonResponse(200, f)
+		}
+		placed := &Order{
+			Id:       "qlbga6b600n3xta7actori10z19acfb20njbtuhtu5xry7z8jswbaycazlkc0wf1",
+			Book:     order.Book,
+			Datetime: time.Date(2015, 11, 12, 12, 33, 47, 0, time.UTC),
+			Type:     "0",
+			Status:   "0",
+			Amount:   order.Amount,
+		}
+		if order.Price != nil {
+			placed.Price = *order.Price
+		}
+		return httpmock.NewJsonResponse(200, placed)
+	}
+	httpmock.RegisterResponder("POST", URL+buyPath, v2)
+	httpmock.RegisterResponder("POST", URL+sellPath, v2)
+
+	httpmock.RegisterResponder("POST", URL+cancelOrderPath,
+		func(req *http.Request) (*http.Response, error) {
+			order := &Order{}
+			if err := json.NewDecoder(req.Body).Decode(order); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			if order.Id == "unknown" {
+				f := fields{Error: Error{Code: 108, Message: "Order not found"}}
+				return httpmock.NewJsonResponse(200, f)
+			}
+			return httpmock.NewJsonResponse(200, "true")
+		},
+	)
+
+	cancelled := func(ids string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response(ids)
+		}
+	}
+	httpmock.RegisterResponder("DELETE", URLv3+ordersPathV3+"543cr2v32a1h6844/",
+		cancelled(`["543cr2v32a1h6844"]`))
+	httpmock.RegisterResponder("DELETE", URLv3+ordersPathV3+"543cr2v32a1h6844-unknown/",
+		cancelled(`["543cr2v32a1h6844"]`))
+
+	httpmock.RegisterResponder("POST", URLv3+ordersPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			order := &placeOrderV3{}
+			if err := json.NewDecoder(req.Body).Decode(order); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			if order.Major.String() == "1000" {
+				return httpmock.NewStringResponse(400, `{"success": false, "error": {"code": "0379", "message": "Insufficient funds"}}`), nil
+			}
+			return v3Response(`{"oid": "qlbga6b600n3xta7"}`)
+		},
+	)
+}
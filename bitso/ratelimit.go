@@ -0,0 +1,168 @@
+package bitso
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+RateLimiter is a token bucket limiting the requests of a client.
+
+It starts full, every request takes a token and the tokens are
+refilled at a steady rate. Once they run out requests wait for the
+next token, or fail with ErrRateLimited when FailFast is set.
+
+The bucket adapts to the rate limit headers of the responses: it's
+drained by the too many requests status and by a lower remaining
+count, and it waits out the Retry-After and reset times.
+*/
+type RateLimiter struct {
+	// FailFast makes the requests fail with ErrRateLimited instead
+	// of waiting when there are no tokens left.
+	FailFast bool
+
+	mutex        sync.Mutex
+	capacity     float64
+	rate         float64 // tokens per second
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+/*
+NewRateLimiter returns a RateLimiter allowing requests per period
+with bursts of up to requests. It panics if requests or per aren't
+positive.
+
+The API allows 60 public requests and 300 private ones per minute:
+
+	client.PublicLimit = bitso.NewRateLimiter(60, time.Minute)
+	client.PrivateLimit = bitso.NewRateLimiter(300, time.Minute)
+*/
+func NewRateLimiter(requests int, per time.Duration) *RateLimiter {
+	if requests <= 0 || per <= 0 {
+		panic("non-positive requests or period for NewRateLimiter")
+	}
+	return &RateLimiter{
+		capacity: float64(requests),
+		rate:     float64(requests) / per.Seconds(),
+		tokens:   float64(requests),
+		last:     time.Now(),
+	}
+}
+
+// Wait takes a token, waiting for it unless l fails fast.
+// A nil RateLimiter never waits.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	wait := l.reserve(time.Now())
+	if wait > 0 && l.FailFast {
+		l.mutex.Unlock()
+		return ErrRateLimited
+	}
+	l.tokens--
+	l.mutex.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// The token wasn't used, give it back.
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve refills the bucket and returns how long a request made
+// at now must wait. The caller must hold the mutex.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	if now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.capacity {
+			l.tokens = l.capacity
+		}
+		l.last = now
+	}
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	if blocked := l.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// update adapts the bucket to the status and the
+// rate limit headers of a response.
+func (l *RateLimiter) update(status int, header http.Header) {
+	if l == nil {
+		return
+	}
+	now := time.Now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if status == http.StatusTooManyRequests && l.tokens > 0 {
+		l.tokens = 0
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		if float64(remaining) < l.tokens {
+			l.tokens = float64(remaining)
+		}
+		if reset, ok := resetTime(header.Get("X-RateLimit-Reset"), now); ok && remaining == 0 {
+			l.block(reset)
+		}
+	}
+	if d, ok := retryAfter(header.Get("Retry-After"), now); ok {
+		l.block(now.Add(d))
+	}
+}
+
+// block makes the requests wait until t. The caller must hold the mutex.
+func (l *RateLimiter) block(t time.Time) {
+	if t.After(l.blockedUntil) {
+		l.blockedUntil = t
+	}
+}
+
+// retryAfter parses a Retry-After header, either
+// a number of seconds or an HTTP date.
+func retryAfter(s string, now time.Time) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Sub(now), true
+}
+
+// resetTime parses a X-RateLimit-Reset header, either a unix
+// time or the number of seconds until the limit is reset.
+func resetTime(s string, now time.Time) (time.Time, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	// Resets are at most minutes away, larger numbers are unix times.
+	if n >= 1e9 {
+		return time.Unix(n, 0), true
+	}
+	return now.Add(time.Duration(n) * time.Second), true
+}
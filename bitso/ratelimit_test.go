@@ -0,0 +1,158 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRateLimiter(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a rate limiter without requests or period", t, func() {
+		Convey("It should not be created", func() {
+			So(func() { NewRateLimiter(0, time.Minute) }, ShouldPanic)
+			So(func() { NewRateLimiter(60, 0) }, ShouldPanic)
+			So(func() { NewRateLimiter(-1, -time.Minute) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a rate limiter allowing 2 requests every 100ms", t, func() {
+		l := NewRateLimiter(2, 100*time.Millisecond)
+		ctx := context.Background()
+
+		Convey("A burst of 2 requests should not wait", func() {
+			start := time.Now()
+			So(l.Wait(ctx), ShouldBeNil)
+			So(l.Wait(ctx), ShouldBeNil)
+			So(time.Since(start), ShouldBeLessThan, 25*time.Millisecond)
+		})
+
+		Convey("The third request should wait for a new token", func() {
+			l.Wait(ctx)
+			l.Wait(ctx)
+			start := time.Now()
+			So(l.Wait(ctx), ShouldBeNil)
+			So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 40*time.Millisecond)
+		})
+
+		Convey("When it fails fast", func() {
+			l.FailFast = true
+			l.Wait(ctx)
+			l.Wait(ctx)
+
+			Convey("The third request should be ErrRateLimited", func() {
+				So(l.Wait(ctx), ShouldEqual, ErrRateLimited)
+			})
+		})
+
+		Convey("When the context is cancelled while waiting", func() {
+			l.Wait(ctx)
+			l.Wait(ctx)
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+			defer cancel()
+
+			Convey("The wait should stop with the error of the context", func() {
+				So(errors.Is(l.Wait(ctx), context.DeadlineExceeded), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a response asks to retry after a second", func() {
+			l.FailFast = true
+			l.update(http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+			Convey("The next request should be limited", func() {
+				So(l.Wait(ctx), ShouldEqual, ErrRateLimited)
+			})
+		})
+
+		Convey("When a response has no requests remaining until the reset", func() {
+			l.FailFast = true
+			reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+			l.update(http.StatusOK, http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {reset},
+			})
+
+			Convey("The next request should be limited", func() {
+				So(l.Wait(ctx), ShouldEqual, ErrRateLimited)
+			})
+		})
+
+		Convey("When a response has fewer requests remaining than tokens", func() {
+			l.FailFast = true
+			l.update(http.StatusOK, http.Header{"X-Ratelimit-Remaining": {"1"}})
+
+			Convey("Only those requests should be allowed", func() {
+				So(l.Wait(ctx), ShouldBeNil)
+				So(l.Wait(ctx), ShouldEqual, ErrRateLimited)
+			})
+		})
+	})
+
+	Convey("Given the rate limit headers", t, func() {
+		now := time.Unix(1500000000, 0)
+
+		Convey("Retry-After should be parsed as seconds or a date", func() {
+			d, ok := retryAfter("30", now)
+			So(ok, ShouldBeTrue)
+			So(d, ShouldEqual, 30*time.Second)
+			d, ok = retryAfter(now.Add(time.Minute).UTC().Format(http.TimeFormat), now)
+			So(ok, ShouldBeTrue)
+			So(d, ShouldEqual, time.Minute)
+			_, ok = retryAfter("", now)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("X-RateLimit-Reset should be parsed as a unix time or seconds", func() {
+			reset, ok := resetTime("1500000060", now)
+			So(ok, ShouldBeTrue)
+			So(reset.Equal(now.Add(time.Minute)), ShouldBeTrue)
+			reset, ok = resetTime("60", now)
+			So(ok, ShouldBeTrue)
+			So(reset.Equal(now.Add(time.Minute)), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a client with separate public and private budgets", t, func() {
+		client := NewClient()
+		client.Version = V3
+		_, err := client.AvailableBooks()
+		So(err, ShouldBeNil)
+		client.PublicLimit = NewRateLimiter(1, time.Minute)
+		client.PublicLimit.FailFast = true
+		client.PrivateLimit = NewRateLimiter(1, time.Minute)
+		client.PrivateLimit.FailFast = true
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+		httpmock.RegisterResponder("GET", URLv3+tickerPathV3,
+			func(req *http.Request) (*http.Response, error) {
+				return v3Response(`{"book": "btc_mxn"}`)
+			},
+		)
+		httpmock.RegisterResponder("GET", URLv3+balancePathV3,
+			func(req *http.Request) (*http.Response, error) {
+				return v3Response(`{"balances": []}`)
+			},
+		)
+
+		Convey("When the public budget is spent", func() {
+			_, err := client.Ticker(BTCMXN)
+			So(err, ShouldBeNil)
+			_, tickerErr := client.Ticker(BTCMXN)
+			_, balanceErr := account.Balance()
+
+			Convey("Only the public calls should be limited", func() {
+				So(errors.Is(tickerErr, ErrRateLimited), ShouldBeTrue)
+				So(balanceErr, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+/*
+RetryPolicy tells how the calls that are safe to repeat are retried:
+the public ones and the private ones that don't change the account.
+
+Every retry waits twice as long as the previous one, starting at
+MinBackoff and up to MaxBackoff, shortened by a random fraction of
+up to Jitter so that clients failing together don't retry together.
+*/
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a call is made,
+	// the first one included. One or less disables the retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between retries.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of every wait, between 0 and 1,
+	// that is randomized.
+	Jitter float64
+	// Statuses are the HTTP statuses of the responses retried.
+	// Leaving it nil uses the too many requests status and the
+	// statuses of a failing or unavailable server.
+	Statuses []int
+	// Retryable reports whether a call failing with err is
+	// retried. Leaving it nil retries the network errors and the
+	// responses with one of Statuses.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy is the policy of the clients without one.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+	Jitter:      0.5,
+}
+
+var defaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type safeRetriesKey struct{}
+
+/*
+WithSafeRetries returns a copy of ctx marking the calls made with it
+as safe to retry, even the ones that change the account like placing
+or cancelling orders.
+
+Retrying them may repeat a call the exchange received but couldn't
+answer, only use it when that's harmless.
+*/
+func WithSafeRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, safeRetriesKey{}, true)
+}
+
+func safeRetries(ctx context.Context) bool {
+	safe, _ := ctx.Value(safeRetriesKey{}).(bool)
+	return safe
+}
+
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.Retry == nil {
+		return DefaultRetryPolicy
+	}
+	return c.Retry
+}
+
+// retry calls call until it succeeds, fails with an error that isn't
+// retryable or the attempts run out. Calls that aren't idempotent
+// are only made once, unless ctx marks them as safe to retry.
+func (c *Client) retry(ctx context.Context, idempotent bool, call func() error) error {
+	p := c.retryPolicy()
+	err := call()
+	if !idempotent && !safeRetries(ctx) {
+		return err
+	}
+	for attempt := 1; attempt < p.MaxAttempts && err != nil; attempt++ {
+		if ctx.Err() != nil || !p.retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = call()
+	}
+	return err
+}
+
+// backoff returns the wait before the given retry, the first one is 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	statuses := p.Statuses
+	if statuses == nil {
+		statuses = defaultRetryStatuses
+	}
+	status := 0
+	var apiErr *APIError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.Status
+	case errors.As(err, &httpErr):
+		status = httpErr.Status
+	default:
+		// Every failure of the transport is an *url.Error,
+		// only the ones of the network are retried.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
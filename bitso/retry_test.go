@@ -0,0 +1,186 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// failingResponder answers the first failures calls with status
+// and the next ones with a successful v3 response of payload.
+func failingResponder(failures int, status int, payload string) (httpmock.Responder, *int) {
+	calls := 0
+	return func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls <= failures {
+			return httpmock.NewStringResponse(status, `{}`), nil
+		}
+		return v3Response(payload)
+	}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a retry policy without jitter", t, func() {
+		p := &RetryPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}
+
+		Convey("The backoff should double up to the maximum", func() {
+			So(p.backoff(1), ShouldEqual, 10*time.Millisecond)
+			So(p.backoff(2), ShouldEqual, 20*time.Millisecond)
+			So(p.backoff(3), ShouldEqual, 25*time.Millisecond)
+			So(p.backoff(30), ShouldEqual, 25*time.Millisecond)
+		})
+
+		Convey("When jitter is added", func() {
+			p.Jitter = 0.5
+
+			Convey("The backoff should be shortened by up to half", func() {
+				for i := 0; i < 100; i++ {
+					d := p.backoff(2)
+					So(d, ShouldBeGreaterThan, 10*time.Millisecond)
+					So(d, ShouldBeLessThanOrEqualTo, 20*time.Millisecond)
+				}
+			})
+		})
+	})
+
+	Convey("Given the default retryable classes", t, func() {
+		p := &RetryPolicy{}
+
+		Convey("Failing and rate limited responses should be retried", func() {
+			So(p.retryable(&HTTPError{Status: http.StatusServiceUnavailable}), ShouldBeTrue)
+			So(p.retryable(&APIError{Status: http.StatusTooManyRequests}), ShouldBeTrue)
+		})
+
+		Convey("Rejected requests should not be retried", func() {
+			So(p.retryable(&HTTPError{Status: http.StatusBadRequest}), ShouldBeFalse)
+			So(p.retryable(&APIError{Code: "0201", Status: http.StatusUnauthorized}), ShouldBeFalse)
+			So(p.retryable(&APIError{Code: "101"}), ShouldBeFalse)
+		})
+
+		Convey("Network errors should be retried", func() {
+			err := &url.Error{Op: "Get", URL: URLv3, Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+			So(p.retryable(err), ShouldBeTrue)
+		})
+
+		Convey("Other errors of the transport should not be retried", func() {
+			err := &url.Error{Op: "Get", URL: URLv3, Err: errors.New("unsupported protocol scheme")}
+			So(p.retryable(err), ShouldBeFalse)
+		})
+
+		Convey("When the statuses are configured", func() {
+			p.Statuses = []int{http.StatusBadGateway}
+
+			Convey("Only those should be retried", func() {
+				So(p.retryable(&HTTPError{Status: http.StatusBadGateway}), ShouldBeTrue)
+				So(p.retryable(&HTTPError{Status: http.StatusServiceUnavailable}), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a client using the v3 API with a fast retry policy", t, func() {
+		client := NewClient()
+		client.Version = V3
+		client.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+
+		Convey("When the ticker fails twice", func() {
+			responder, calls := failingResponder(2, http.StatusServiceUnavailable, `{"book": "btc_mxn", "last": "5600.00"}`)
+			httpmock.RegisterResponder("GET", URLv3+tickerPathV3, responder)
+			ticker, err := client.Ticker(BTCMXN)
+
+			Convey("The third attempt should succeed", func() {
+				So(err, ShouldBeNil)
+				So(*calls, ShouldEqual, 3)
+				So(ticker.Last.String(), ShouldEqual, "5600.00")
+			})
+		})
+
+		Convey("When the ticker keeps failing", func() {
+			responder, calls := failingResponder(5, http.StatusBadGateway, `{}`)
+			httpmock.RegisterResponder("GET", URLv3+tickerPathV3, responder)
+			_, err := client.Ticker(BTCMXN)
+
+			Convey("The last error should be returned once the attempts run out", func() {
+				var httpErr *HTTPError
+				So(errors.As(err, &httpErr), ShouldBeTrue)
+				So(httpErr.Status, ShouldEqual, http.StatusBadGateway)
+				So(*calls, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When the balance fails once", func() {
+			var nonces []string
+			calls := 0
+			httpmock.RegisterResponder("GET", URLv3+balancePathV3,
+				func(req *http.Request) (*http.Response, error) {
+					calls++
+					if !authorizeV3(req) {
+						return v3Unauthorized()
+					}
+					nonces = append(nonces, req.Header.Get("Authorization"))
+					if calls == 1 {
+						return httpmock.NewStringResponse(http.StatusInternalServerError, `{}`), nil
+					}
+					return v3Response(`{"balances": []}`)
+				},
+			)
+			_, err := account.Balance()
+
+			Convey("It should be retried with a new signature", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 2)
+				So(nonces[0], ShouldNotEqual, nonces[1])
+			})
+		})
+
+		Convey("When an order fails to be placed", func() {
+			responder, calls := failingResponder(1, http.StatusServiceUnavailable, `{"oid": "qlbga6b600n3xta7"}`)
+			httpmock.RegisterResponder("POST", URLv3+ordersPathV3, responder)
+			_, err := account.Buy(BTCMXN, MustParseDecimal("0.01"), MustParseDecimal("5600.00"))
+
+			Convey("It should not be retried", func() {
+				So(err, ShouldNotBeNil)
+				So(*calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When an order marked as safe to retry fails to be placed", func() {
+			responder, calls := failingResponder(1, http.StatusServiceUnavailable, `{"oid": "qlbga6b600n3xta7"}`)
+			httpmock.RegisterResponder("POST", URLv3+ordersPathV3, responder)
+			ctx := WithSafeRetries(context.Background())
+			order, err := account.BuyContext(ctx, BTCMXN, MustParseDecimal("0.01"), MustParseDecimal("5600.00"))
+
+			Convey("It should be retried", func() {
+				So(err, ShouldBeNil)
+				So(*calls, ShouldEqual, 2)
+				So(order.Id, ShouldEqual, "qlbga6b600n3xta7")
+			})
+		})
+
+		Convey("When the context is cancelled while waiting to retry", func() {
+			client.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}
+			responder, calls := failingResponder(5, http.StatusServiceUnavailable, `{}`)
+			httpmock.RegisterResponder("GET", URLv3+tickerPathV3, responder)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, err := client.TickerContext(ctx, BTCMXN)
+
+			Convey("The call should stop without retrying", func() {
+				var httpErr *HTTPError
+				So(errors.As(err, &httpErr), ShouldBeTrue)
+				So(*calls, ShouldEqual, 1)
+			})
+		})
+	})
+}
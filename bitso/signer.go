@@ -0,0 +1,128 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+)
+
+// Signer signs the messages of the private requests, returning the
+// hex encoded HMAC-SHA256 of message keyed by the secret of the key.
+type Signer interface {
+	Sign(ctx context.Context, message []byte) (string, error)
+}
+
+// HMACSigner is a Signer holding the secret in memory, it's
+// used by the accounts without a Signer.
+type HMACSigner struct {
+	secret string
+}
+
+// NewHMACSigner returns a Signer signing with secret.
+func NewHMACSigner(secret string) *HMACSigner {
+	return &HMACSigner{secret: secret}
+}
+
+func (s *HMACSigner) Sign(ctx context.Context, message []byte) (string, error) {
+	return sign(string(message), s.secret), nil
+}
+
+var errUnknownKey = errors.New("Unknown key")
+
+// signRequest and signResponse are the messages of the signer
+// protocol, a JSON line each way over a new connection.
+type signRequest struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+/*
+SocketSigner is a Signer delegating to a signing daemon listening
+on a unix socket, so the secret never enters the process.
+
+Every signature is requested through a new connection: a JSON line
+with the key and the message, answered by a JSON line with either the
+signature or an error. ServeSigner implements the daemon side.
+*/
+type SocketSigner struct {
+	// Path is the path of the unix socket.
+	Path string
+	// Key is the API key whose secret signs the messages.
+	Key string
+}
+
+func (s *SocketSigner) Sign(ctx context.Context, message []byte) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", s.Path)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// Closing the connection once ctx is done unblocks the
+	// reads and writes, even when ctx has no deadline.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+	if err := json.NewEncoder(conn).Encode(&signRequest{Key: s.Key, Message: string(message)}); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	resp := &signResponse{}
+	if err := json.NewDecoder(conn).Decode(resp); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", errors.New(resp.Error)
+	}
+	return resp.Signature, nil
+}
+
+/*
+ServeSigner answers the requests of SocketSigner accepted by l,
+signing them with the signer of their key. It returns when l
+fails, like once it's closed.
+*/
+func ServeSigner(l net.Listener, signers map[string]Signer) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go serveSignature(conn, signers)
+	}
+}
+
+func serveSignature(conn net.Conn, signers map[string]Signer) {
+	defer conn.Close()
+	req := &signRequest{}
+	if err := json.NewDecoder(conn).Decode(req); err != nil {
+		return
+	}
+	resp := &signResponse{}
+	signer, ok := signers[req.Key]
+	if !ok {
+		resp.Error = errUnknownKey.Error()
+	} else if signature, err := signer.Sign(context.Background(), []byte(req.Message)); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Signature = signature
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
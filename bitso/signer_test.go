@@ -0,0 +1,143 @@
+package bitso
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// failingSigner can't sign any message.
+type failingSigner struct{}
+
+func (failingSigner) Sign(ctx context.Context, message []byte) (string, error) {
+	return "", errors.New("signer unavailable")
+}
+
+func TestSigner(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an HMAC signer", t, func() {
+		signer := NewHMACSigner("secret")
+
+		Convey("It should sign like the accounts without a signer", func() {
+			signature, err := signer.Sign(context.Background(), []byte("message"))
+			So(err, ShouldBeNil)
+			So(signature, ShouldEqual, sign("message", "secret"))
+		})
+	})
+
+	Convey("Given a signing daemon listening on a unix socket", t, func() {
+		dir, err := ioutil.TempDir("", "signer")
+		So(err, ShouldBeNil)
+		path := filepath.Join(dir, "signer.sock")
+		l, err := net.Listen("unix", path)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			l.Close()
+			os.RemoveAll(dir)
+		})
+		go ServeSigner(l, map[string]Signer{"key": NewHMACSigner("secret")})
+
+		Convey("When a message is signed through the socket", func() {
+			signer := &SocketSigner{Path: path, Key: "key"}
+			signature, err := signer.Sign(context.Background(), []byte("message"))
+
+			Convey("It should be signed with the secret of the daemon", func() {
+				So(err, ShouldBeNil)
+				So(signature, ShouldEqual, sign("message", "secret"))
+			})
+		})
+
+		Convey("When a message is signed with an unknown key", func() {
+			signer := &SocketSigner{Path: path, Key: "unknown"}
+			_, err := signer.Sign(context.Background(), []byte("message"))
+
+			Convey("err should be the one of the daemon", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, errUnknownKey.Error())
+			})
+		})
+
+		Convey("When an account without the secret uses it", func() {
+			client := NewClient()
+			client.Version = V3
+			account := client.Authenticate(&Keys{Key: "key", ClientId: "clientId"})
+			account.Signer = &SocketSigner{Path: path, Key: "key"}
+			httpmock.RegisterResponder("GET", URLv3+balancePathV3,
+				func(req *http.Request) (*http.Response, error) {
+					if !authorizeV3(req) {
+						return v3Unauthorized()
+					}
+					return v3Response(`{"balances": []}`)
+				},
+			)
+			_, err := account.Balance()
+
+			Convey("The requests should be signed by the daemon", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a signing daemon that never answers", t, func() {
+		dir, err := ioutil.TempDir("", "signer")
+		So(err, ShouldBeNil)
+		path := filepath.Join(dir, "signer.sock")
+		l, err := net.Listen("unix", path)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			l.Close()
+			os.RemoveAll(dir)
+		})
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+
+		Convey("When the context of a signature without a deadline is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			signer := &SocketSigner{Path: path, Key: "key"}
+			_, err := signer.Sign(ctx, []byte("message"))
+
+			Convey("err should be context.Canceled", func() {
+				So(err, ShouldEqual, context.Canceled)
+			})
+		})
+	})
+
+	Convey("Given an account without keys", t, func() {
+		account := &Account{Signer: failingSigner{}}
+
+		Convey("Signing should panic before reading the keys", func() {
+			So(func() { account.getSignature(context.Background(), 1) }, ShouldPanicWith, "can't generate a signature without keys")
+		})
+	})
+
+	Convey("Given an account whose signer fails", t, func() {
+		account := Authenticate(&Keys{Key: "key", ClientId: "clientId"})
+		account.Signer = failingSigner{}
+		_, err := account.Balance()
+
+		Convey("The request should fail with the error of the signer", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "signer unavailable")
+		})
+	})
+}
@@ -0,0 +1,359 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamURL is the address of the websocket feed.
+const StreamURL = "wss://ws.bitso.com"
+
+// streamBuffer is the capacity of every message channel.
+const streamBuffer = 64
+
+// Channel is a kind of message of the websocket feed.
+type Channel string
+
+const (
+	ChannelTrades     Channel = "trades"
+	ChannelOrders     Channel = "orders"
+	ChannelDiffOrders Channel = "diff-orders"
+)
+
+var errStreamClosed = errors.New("Stream closed")
+
+// TradesMessage holds the trades executed in Book.
+type TradesMessage struct {
+	Book   Book
+	Trades []*StreamTrade
+}
+
+// StreamTrade is a trade received from the websocket feed. Side is
+// the side of the maker, like the Side of a Transaction.
+type StreamTrade struct {
+	Tid          int64
+	Amount       Decimal
+	Price        Decimal
+	Value        Decimal
+	Side         string
+	MakerOrderId string
+	TakerOrderId string
+}
+
+// OrdersMessage holds the top of the order book of Book.
+type OrdersMessage struct {
+	Book Book
+	Bids []*StreamOrder
+	Asks []*StreamOrder
+}
+
+// DiffOrdersMessage holds the changes made to the order book of
+// Book. Sequence increases by one with every message of the book.
+type DiffOrdersMessage struct {
+	Book     Book
+	Sequence int64
+	Orders   []*StreamOrder
+}
+
+// StreamOrder is an order received from the websocket feed.
+// OrderId and Status are only set by the diff-orders channel.
+type StreamOrder struct {
+	OrderId   string
+	Side      string
+	Price     Decimal
+	Amount    Decimal
+	Value     Decimal
+	Status    string
+	Timestamp time.Time
+}
+
+type subscription struct {
+	Action string  `json:"action"`
+	Book   Book    `json:"book"`
+	Type   Channel `json:"type"`
+}
+
+type streamMessage struct {
+	Type     Channel         `json:"type"`
+	Book     Book            `json:"book"`
+	Sequence int64           `json:"sequence"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+type streamTrade struct {
+	Tid          int64   `json:"i"`
+	Amount       Decimal `json:"a"`
+	Rate         Decimal `json:"r"`
+	Value        Decimal `json:"v"`
+	MakerSide    int     `json:"t"`
+	MakerOrderId string  `json:"mo"`
+	TakerOrderId string  `json:"to"`
+}
+
+type streamOrder struct {
+	Timestamp timestamp `json:"d"`
+	Rate      Decimal   `json:"r"`
+	Side      int       `json:"t"`
+	Amount    Decimal   `json:"a"`
+	Value     Decimal   `json:"v"`
+	OrderId   string    `json:"o"`
+	Status    string    `json:"s"`
+}
+
+type streamOrders struct {
+	Bids []*streamOrder `json:"bids"`
+	Asks []*streamOrder `json:"asks"`
+}
+
+// streamSide converts the numeric sides of the feed,
+// 0 is buy and 1 is sell.
+func streamSide(side int) string {
+	if side == 1 {
+		return Sell
+	}
+	return Buy
+}
+
+func (o *streamOrder) order() *StreamOrder {
+	return &StreamOrder{
+		OrderId:   o.OrderId,
+		Side:      streamSide(o.Side),
+		Price:     o.Rate,
+		Amount:    o.Amount,
+		Value:     o.Value,
+		Status:    o.Status,
+		Timestamp: o.Timestamp.Time(),
+	}
+}
+
+func streamOrdersFrom(o []*streamOrder) []*StreamOrder {
+	orders := make([]*StreamOrder, len(o))
+	for i, order := range o {
+		orders[i] = order.order()
+	}
+	return orders
+}
+
+/*
+Stream is a connection to the websocket feed.
+
+Messages of every subscribed channel are delivered through the
+channel returned by Trades, Orders and DiffOrders, which must be
+drained for the stream to keep reading. They are closed when the
+connection ends, Err tells why.
+*/
+type Stream struct {
+	client     *Client
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+	trades     chan *TradesMessage
+	orders     chan *OrdersMessage
+	diffOrders chan *DiffOrdersMessage
+	err        error
+	done       chan struct{}
+	closing    chan struct{}
+	closeOnce  sync.Once
+}
+
+// DialStream connects to the websocket feed using the DefaultClient.
+func DialStream() (*Stream, error) {
+	return DefaultClient.DialStream()
+}
+
+// DialStreamContext is like DialStream with a context.
+func DialStreamContext(ctx context.Context) (*Stream, error) {
+	return DefaultClient.DialStreamContext(ctx)
+}
+
+// DialStream connects to the websocket feed.
+func (c *Client) DialStream() (*Stream, error) {
+	return c.DialStreamContext(context.Background())
+}
+
+// DialStreamContext is like DialStream with a context.
+func (c *Client) DialStreamContext(ctx context.Context) (*Stream, error) {
+	u := c.StreamURL
+	if u == "" {
+		u = StreamURL
+	}
+	header := http.Header{}
+	if c.UserAgent != "" {
+		header.Set("User-Agent", c.UserAgent)
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u, header)
+	if err != nil {
+		return nil, err
+	}
+	s := &Stream{
+		client:     c,
+		conn:       conn,
+		trades:     make(chan *TradesMessage, streamBuffer),
+		orders:     make(chan *OrdersMessage, streamBuffer),
+		diffOrders: make(chan *DiffOrdersMessage, streamBuffer),
+		done:       make(chan struct{}),
+		closing:    make(chan struct{}),
+	}
+	go s.read()
+	return s, nil
+}
+
+// Subscribe starts receiving the messages of channel for book.
+func (s *Stream) Subscribe(book Book, channel Channel) error {
+	return s.SubscribeContext(context.Background(), book, channel)
+}
+
+// SubscribeContext is like Subscribe with a context.
+func (s *Stream) SubscribeContext(ctx context.Context, book Book, channel Channel) error {
+	if err := s.client.validateBook(ctx, book); err != nil {
+		return err
+	}
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	return s.conn.WriteJSON(&subscription{
+		Action: "subscribe",
+		Book:   book,
+		Type:   channel,
+	})
+}
+
+// Trades returns the channel receiving the trades messages.
+func (s *Stream) Trades() <-chan *TradesMessage {
+	return s.trades
+}
+
+// Orders returns the channel receiving the orders messages.
+func (s *Stream) Orders() <-chan *OrdersMessage {
+	return s.orders
+}
+
+// DiffOrders returns the channel receiving the diff-orders messages.
+func (s *Stream) DiffOrders() <-chan *DiffOrdersMessage {
+	return s.diffOrders
+}
+
+// Done is closed when the stream stops reading.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the reason the stream stopped, it's
+// only set once Done is closed.
+func (s *Stream) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close ends the connection.
+func (s *Stream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+	})
+	return s.conn.Close()
+}
+
+func (s *Stream) isClosing() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Stream) read() {
+	defer func() {
+		close(s.trades)
+		close(s.orders)
+		close(s.diffOrders)
+		close(s.done)
+	}()
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.err = err
+			if s.isClosing() || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				s.err = errStreamClosed
+			}
+			return
+		}
+		if err := s.dispatch(data); err != nil {
+			s.err = err
+			s.conn.Close()
+			return
+		}
+	}
+}
+
+func (s *Stream) dispatch(data []byte) error {
+	m := &streamMessage{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return err
+	}
+	// Subscription acknowledgements and keep alive
+	// messages don't carry a payload.
+	if len(m.Payload) == 0 {
+		return nil
+	}
+	switch m.Type {
+	case ChannelTrades:
+		var trades []*streamTrade
+		if err := json.Unmarshal(m.Payload, &trades); err != nil {
+			return err
+		}
+		message := &TradesMessage{Book: m.Book, Trades: make([]*StreamTrade, len(trades))}
+		for i, t := range trades {
+			message.Trades[i] = &StreamTrade{
+				Tid:          t.Tid,
+				Amount:       t.Amount,
+				Price:        t.Rate,
+				Value:        t.Value,
+				Side:         streamSide(t.MakerSide),
+				MakerOrderId: t.MakerOrderId,
+				TakerOrderId: t.TakerOrderId,
+			}
+		}
+		select {
+		case s.trades <- message:
+		case <-s.closing:
+		}
+	case ChannelOrders:
+		orders := &streamOrders{}
+		if err := json.Unmarshal(m.Payload, orders); err != nil {
+			return err
+		}
+		message := &OrdersMessage{
+			Book: m.Book,
+			Bids: streamOrdersFrom(orders.Bids),
+			Asks: streamOrdersFrom(orders.Asks),
+		}
+		select {
+		case s.orders <- message:
+		case <-s.closing:
+		}
+	case ChannelDiffOrders:
+		var orders []*streamOrder
+		if err := json.Unmarshal(m.Payload, &orders); err != nil {
+			return err
+		}
+		message := &DiffOrdersMessage{
+			Book:     m.Book,
+			Sequence: m.Sequence,
+			Orders:   streamOrdersFrom(orders),
+		}
+		select {
+		case s.diffOrders <- message:
+		case <-s.closing:
+		}
+	}
+	return nil
+}
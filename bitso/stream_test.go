@@ -0,0 +1,125 @@
+package bitso
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var streamMessages = map[Channel]string{
+	ChannelTrades: `{"type": "trades", "book": "btc_mxn", "payload": [
+		{"i": 8027, "a": "0.08123", "r": "5600.00", "v": "454.888", "t": 1, "mo": "maker", "to": "taker"}
+	]}`,
+	ChannelOrders: `{"type": "orders", "book": "btc_mxn", "payload": {
+		"bids": [{"r": "5600.00", "a": "0.5", "v": "2800.00", "t": 0, "d": 1455315979682}],
+		"asks": [{"r": "5650.00", "a": "1.0", "v": "5650.00", "t": 1, "d": 1455315979683}]
+	}}`,
+	ChannelDiffOrders: `{"type": "diff-orders", "book": "btc_mxn", "sequence": 2734, "payload": [
+		{"d": 1455315979682, "r": "5600.00", "t": 0, "a": "0.5", "v": "2800.00", "o": "Gcg2dDjNBR5XSRcp", "s": "open"}
+	]}`,
+}
+
+// newStreamServer returns a stand-in of the websocket feed that
+// acknowledges every subscription and sends a message of its channel.
+func newStreamServer() *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			sub := &subscription{}
+			if err := conn.ReadJSON(sub); err != nil {
+				return
+			}
+			ack := `{"action": "subscribe", "response": "ok", "time": 1455831538045, "type": "` + string(sub.Type) + `"}`
+			conn.WriteMessage(websocket.TextMessage, []byte(ack))
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "ka"}`))
+			conn.WriteMessage(websocket.TextMessage, []byte(streamMessages[sub.Type]))
+		}
+	}))
+}
+
+func TestStream(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	defer httpmock.DeactivateAndReset()
+	server := newStreamServer()
+	defer server.Close()
+
+	Convey("Given a stream connected to the feed", t, func() {
+		client := NewClient()
+		client.StreamURL = "ws" + strings.TrimPrefix(server.URL, "http")
+		stream, err := client.DialStream()
+		So(err, ShouldBeNil)
+		defer stream.Close()
+
+		Convey("When the trades are subscribed", func() {
+			err := stream.Subscribe(BTCMXN, ChannelTrades)
+			So(err, ShouldBeNil)
+			message := <-stream.Trades()
+
+			Convey("The trades should be typed", func() {
+				So(message.Book, ShouldEqual, BTCMXN)
+				So(message.Trades, ShouldHaveLength, 1)
+				So(message.Trades[0].Tid, ShouldEqual, 8027)
+				So(message.Trades[0].Price.String(), ShouldEqual, "5600.00")
+				So(message.Trades[0].Side, ShouldEqual, Sell)
+				So(message.Trades[0].MakerOrderId, ShouldEqual, "maker")
+			})
+		})
+
+		Convey("When the orders are subscribed", func() {
+			err := stream.Subscribe(BTCMXN, ChannelOrders)
+			So(err, ShouldBeNil)
+			message := <-stream.Orders()
+
+			Convey("The bids and asks should be typed", func() {
+				So(message.Bids[0].Side, ShouldEqual, Buy)
+				So(message.Asks[0].Price.String(), ShouldEqual, "5650.00")
+				So(message.Asks[0].Timestamp.UnixMilli(), ShouldEqual, 1455315979683)
+			})
+		})
+
+		Convey("When the diff-orders are subscribed", func() {
+			err := stream.Subscribe(BTCMXN, ChannelDiffOrders)
+			So(err, ShouldBeNil)
+			message := <-stream.DiffOrders()
+
+			Convey("The sequence and orders should be typed", func() {
+				So(message.Sequence, ShouldEqual, 2734)
+				So(message.Orders[0].OrderId, ShouldEqual, "Gcg2dDjNBR5XSRcp")
+				So(message.Orders[0].Status, ShouldEqual, "open")
+			})
+		})
+
+		Convey("When an invalid book is subscribed", func() {
+			err := stream.Subscribe("invalid_book", ChannelTrades)
+
+			Convey("An error should occur", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the stream is closed", func() {
+			stream.Close()
+			<-stream.Done()
+
+			Convey("err should be errStreamClosed", func() {
+				So(stream.Err(), ShouldEqual, errStreamClosed)
+			})
+
+			Convey("The channels should be closed", func() {
+				_, ok := <-stream.Trades()
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package bitso
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var errInvalidTime = errors.New("Invalid time value")
+
+// timeLayouts are the formats of the dates returned by the API,
+// dates without a zone are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05",
+}
+
+// maxUnixSeconds tells unix times in seconds from the ones in
+// milliseconds, it's far beyond any date the API returns.
+const maxUnixSeconds = 1e12
+
+/*
+parseTime parses the dates of every endpoint: unix times in seconds
+or milliseconds, RFC 3339 dates with or without a colon in the zone
+and the v2 dates without a zone. Empty dates are the zero time.
+*/
+func parseTime(s string) (time.Time, error) {
+	if s == "" || s == "null" {
+		return time.Time{}, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n >= maxUnixSeconds {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errInvalidTime
+}
+
+// timestamp decodes a JSON string or number with parseTime.
+type timestamp time.Time
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	parsed, err := parseTime(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*t = timestamp(parsed)
+	return nil
+}
+
+func (t timestamp) Time() time.Time {
+	return time.Time(t)
+}
@@ -0,0 +1,65 @@
+package bitso
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseTime(t *testing.T) {
+	Convey("Given the date formats of the API", t, func() {
+		expected := time.Date(2016, 4, 8, 17, 52, 31, 0, time.UTC)
+
+		Convey("Every format should be parsed", func() {
+			for _, s := range []string{
+				"1460137951",
+				"1460137951000",
+				"2016-04-08T17:52:31.000+00:00",
+				"2016-04-08T17:52:31+0000",
+				"2016-04-08T14:52:31-03:00",
+				"2016-04-08 17:52:31",
+			} {
+				parsed, err := parseTime(s)
+				So(err, ShouldBeNil)
+				So(parsed.Equal(expected), ShouldBeTrue)
+			}
+		})
+
+		Convey("Milliseconds should be kept", func() {
+			parsed, err := parseTime("1455315979682")
+			So(err, ShouldBeNil)
+			So(parsed.Nanosecond(), ShouldEqual, 682*int(time.Millisecond))
+		})
+
+		Convey("Empty dates should be the zero time", func() {
+			parsed, err := parseTime("")
+			So(err, ShouldBeNil)
+			So(parsed.IsZero(), ShouldBeTrue)
+		})
+
+		Convey("Unknown formats should be an error", func() {
+			_, err := parseTime("08/04/2016")
+			So(err, ShouldEqual, errInvalidTime)
+		})
+	})
+
+	Convey("Given a JSON object with dates", t, func() {
+		var v struct {
+			String timestamp `json:"string"`
+			Number timestamp `json:"number"`
+			Null   timestamp `json:"null"`
+		}
+		err := json.Unmarshal([]byte(`{"string": "2015-11-12 12:33:47", "number": 1447331627, "null": null}`), &v)
+
+		Convey("err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Strings, numbers and null should be decoded", func() {
+			So(v.String.Time().Equal(v.Number.Time()), ShouldBeTrue)
+			So(v.Null.Time().IsZero(), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,205 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	userTransactionsPath = "user_transactions"
+	userTradesPathV3     = "user_trades/"
+	// Ascending and Descending are the sort directions of a Page.
+	Ascending  = "asc"
+	Descending = "desc"
+	// userTransactionTrade is the type of the v2 user
+	// transactions that are trades.
+	userTransactionTrade = 2
+)
+
+/*
+Page selects a slice of a listing.
+
+Marker is the id the listing starts after, Sort is Ascending or
+Descending and Limit caps the number of results. Zero values use
+the defaults of the API. The v2 API has no markers, so Marker is
+sent as the numeric offset instead.
+*/
+type Page struct {
+	Marker string
+	Sort   string
+	Limit  int
+}
+
+func (p *Page) values(v *url.Values) {
+	if p == nil {
+		return
+	}
+	if p.Marker != "" {
+		v.Set("marker", p.Marker)
+	}
+	if p.Sort != "" {
+		v.Set("sort", p.Sort)
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+}
+
+// Fill is a trade executed against one of the account orders.
+type Fill struct {
+	Id          string
+	OrderId     string
+	Book        Book
+	Side        string
+	Price       Decimal
+	Amount      Decimal
+	Minor       Decimal
+	Fee         Decimal
+	FeeCurrency Currency
+	Datetime    time.Time
+}
+
+type userTransactions struct {
+	fields
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Sort   string `json:"sort,omitempty"`
+	Book   Book   `json:"book,omitempty"`
+}
+
+type userTradeV3 struct {
+	Book         Book      `json:"book"`
+	Major        Decimal   `json:"major"`
+	Minor        Decimal   `json:"minor"`
+	CreatedAt    timestamp `json:"created_at"`
+	FeesAmount   Decimal   `json:"fees_amount"`
+	FeesCurrency Currency  `json:"fees_currency"`
+	Price        Decimal   `json:"price"`
+	Tid          int64     `json:"tid"`
+	Oid          string    `json:"oid"`
+	Side         string    `json:"side"`
+}
+
+func (t *userTradeV3) fill() *Fill {
+	return &Fill{
+		Id:          strconv.FormatInt(t.Tid, 10),
+		OrderId:     t.Oid,
+		Book:        t.Book,
+		Side:        t.Side,
+		Price:       t.Price,
+		Amount:      t.Major.Abs(),
+		Minor:       t.Minor.Abs(),
+		Fee:         t.FeesAmount,
+		FeeCurrency: t.FeesCurrency,
+		Datetime:    t.CreatedAt.Time(),
+	}
+}
+
+// UserTrades returns the trades executed against the account
+// orders in book, paginated by page. page may be nil.
+func (c *Account) UserTrades(book Book, page *Page) ([]*Fill, error) {
+	return c.UserTradesContext(context.Background(), book, page)
+}
+
+// UserTradesContext is like UserTrades with a context.
+func (c *Account) UserTradesContext(ctx context.Context, book Book, page *Page) ([]*Fill, error) {
+	if err := c.client.validateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	if c.client.Version == V3 {
+		return c.userTradesV3(ctx, book, page)
+	}
+	return c.userTradesV2(ctx, book, page)
+}
+
+func (c *Account) userTradesV3(ctx context.Context, book Book, page *Page) ([]*Fill, error) {
+	v := &url.Values{}
+	v.Set("book", book.String())
+	page.values(v)
+	var trades []*userTradeV3
+	if err := c.request(ctx, "GET", userTradesPathV3, v, nil, &trades); err != nil {
+		return nil, err
+	}
+	fills := make([]*Fill, len(trades))
+	for i, trade := range trades {
+		fills[i] = trade.fill()
+	}
+	return fills, nil
+}
+
+func (c *Account) userTradesV2(ctx context.Context, book Book, page *Page) ([]*Fill, error) {
+	req := &userTransactions{Book: book}
+	if page != nil {
+		if page.Marker != "" {
+			offset, err := strconv.Atoi(page.Marker)
+			if err != nil {
+				return nil, errors.New("Invalid marker, the v2 API expects an offset")
+			}
+			req.Offset = offset
+		}
+		req.Sort = page.Sort
+		req.Limit = page.Limit
+	}
+	var transactions []map[string]json.RawMessage
+	if err := c.post(ctx, userTransactionsPath, req, &transactions); err != nil {
+		return nil, err
+	}
+	major, minor := book.Major(), book.Minor()
+	var fills []*Fill
+	for _, t := range transactions {
+		if value(t, "type") != strconv.Itoa(userTransactionTrade) {
+			continue
+		}
+		amount := decimalValue(t, major.String())
+		fill := &Fill{
+			Id:          value(t, "id"),
+			OrderId:     value(t, "order_id"),
+			Book:        book,
+			Side:        Buy,
+			Price:       decimalValue(t, "rate"),
+			Amount:      amount.Abs(),
+			Minor:       decimalValue(t, minor.String()).Abs(),
+			Fee:         decimalValue(t, "fee"),
+			FeeCurrency: major,
+			Datetime:    timeValue(t, "datetime"),
+		}
+		if amount.Sign() < 0 {
+			fill.Side = Sell
+			fill.FeeCurrency = minor
+		}
+		fills = append(fills, fill)
+	}
+	return fills, nil
+}
+
+// value returns the text of the key of a JSON object, strings
+// unquoted and numbers with their digits as they were sent.
+func value(m map[string]json.RawMessage, key string) string {
+	raw, ok := m[key]
+	if !ok || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// decimalValue parses the key of a JSON object,
+// missing or invalid values are zero.
+func decimalValue(m map[string]json.RawMessage, key string) Decimal {
+	d, _ := ParseDecimal(value(m, key))
+	return d
+}
+
+// timeValue parses the key of a JSON object,
+// missing or invalid values are the zero time.
+func timeValue(m map[string]json.RawMessage, key string) time.Time {
+	t, _ := parseTime(value(m, key))
+	return t
+}
@@ -0,0 +1,133 @@
+package bitso
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUserTrades(t *testing.T) {
+	httpmock.Activate()
+	registerBooksResponder()
+	registerTradesResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an account using the v2 API", t, func() {
+		account := Authenticate(&Keys{Key: "key", Secret: "secret", ClientId: "clientId"})
+
+		Convey("When the user trades are requested", func() {
+			fills, err := account.UserTrades(BTCMXN, &Page{Limit: 10, Sort: Descending})
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Only the trades should be returned", func() {
+				So(fills, ShouldHaveLength, 2)
+			})
+
+			Convey("A negative major amount should be a sell", func() {
+				So(fills[0].Side, ShouldEqual, Sell)
+				So(fills[0].Amount.String(), ShouldEqual, "0.48233100")
+				So(fills[0].FeeCurrency, ShouldEqual, MXN)
+			})
+
+			Convey("A positive major amount should be a buy", func() {
+				So(fills[1].Side, ShouldEqual, Buy)
+				So(fills[1].OrderId, ShouldEqual, "n3xta7actori10z")
+				So(fills[1].FeeCurrency, ShouldEqual, BTC)
+			})
+
+			Convey("Numeric values should keep their digits", func() {
+				So(fills[1].Id, ShouldEqual, "3")
+				So(fills[1].Amount.String(), ShouldEqual, "0.00134000")
+				So(fills[1].Price.String(), ShouldEqual, "5977.6100000000000001")
+				So(fills[1].Fee.String(), ShouldEqual, "0.00000134")
+			})
+		})
+
+		Convey("When the marker is not an offset", func() {
+			_, err := account.UserTrades(BTCMXN, &Page{Marker: "abc"})
+
+			Convey("An error should occur", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an account using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the user trades are requested after a marker", func() {
+			fills, err := account.UserTrades(BTCMXN, &Page{Marker: "1431", Limit: 1})
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The fills should be typed", func() {
+				So(fills, ShouldHaveLength, 1)
+				So(fills[0].Id, ShouldEqual, "1233")
+				So(fills[0].OrderId, ShouldEqual, "wri0yg8miihs80ngk")
+				So(fills[0].Amount.String(), ShouldEqual, "0.00134000")
+				So(fills[0].Fee.String(), ShouldEqual, "0.00000134")
+				So(fills[0].FeeCurrency, ShouldEqual, BTC)
+			})
+		})
+
+		Convey("When the book is invalid", func() {
+			_, err := account.UserTrades("invalid_book", nil)
+
+			Convey("An error should occur", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func registerTradesResponder() {
+	httpmock.RegisterResponder("POST", URL+userTransactionsPath,
+		func(req *http.Request) (*http.Response, error) {
+			r := &userTransactions{}
+			if err := json.NewDecoder(req.Body).Decode(r); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			if r.Limit != 10 || r.Sort != Descending || r.Book != BTCMXN {
+				return httpmock.NewStringResponse(500, "unexpected request"), nil
+			}
+			return httpmock.NewStringResponse(200, `[
+				{"datetime": "2015-10-10 16:19:33", "method": "Bitcoin", "btc": "-0.48233100", "type": 2, "mxn": "2904.18", "rate": "6021.25", "id": "1", "order_id": "6ya2k1tuqiz7zqz", "fee": "7.26"},
+				{"datetime": "2015-10-09 13:46:39", "method": "SPEI Transfer", "mxn": "-3000.00", "type": 1, "id": "2"},
+				{"datetime": "2015-10-08 11:03:11", "btc": 0.00134000, "type": 2, "mxn": -8.01, "rate": 5977.6100000000000001, "id": 3, "order_id": "n3xta7actori10z", "fee": 0.00000134}
+			]`), nil
+		},
+	)
+
+	httpmock.RegisterResponder("GET", URLv3+userTradesPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			if req.URL.Query().Get("marker") != "1431" {
+				return v3Response(`[]`)
+			}
+			return v3Response(`[{
+				"book": "btc_mxn",
+				"major": "-0.00134000",
+				"created_at": "2016-04-22T20:40:26+0000",
+				"minor": "0.08001000",
+				"fees_amount": "0.00000134",
+				"fees_currency": "btc",
+				"price": "5977.61",
+				"tid": 1233,
+				"oid": "wri0yg8miihs80ngk",
+				"side": "sell"
+			}]`)
+		},
+	)
+}
@@ -0,0 +1,315 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIVersion selects the Bitso API version used by a Client.
+type APIVersion int
+
+const (
+	// V2 uses the v2 endpoints, authenticated with
+	// the key, nonce and signature in the request body.
+	V2 APIVersion = iota
+	// V3 uses the v3 endpoints, authenticated with
+	// the Authorization header.
+	V3
+)
+
+const (
+	URLv3               = "https://api.bitso.com/v3/"
+	tickerPathV3        = "ticker/"
+	orderBookPathV3     = "order_book/"
+	tradesPathV3        = "trades/"
+	balancePathV3       = "balance/"
+	openOrdersPathV3    = "open_orders/"
+	ordersPathV3        = "orders/"
+	authorizationScheme = "Bitso"
+)
+
+var (
+	errV3Only     = errors.New("Only available through the v3 API")
+	errNoOrderIds = errors.New("The order book doesn't provide the order ids")
+)
+
+// envelope wraps every v3 response.
+type envelope struct {
+	Success bool            `json:"success"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   *envelopeError  `json:"error,omitempty"`
+}
+
+type envelopeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func decodeEnvelope(body []byte, schema interface{}) error {
+	e := &envelope{}
+	if err := json.Unmarshal(body, e); err != nil {
+		return err
+	}
+	if !e.Success {
+		if e.Error == nil {
+			return errors.New("Unsuccessful response without error")
+		}
+		return &APIError{Code: e.Error.Code, Message: e.Error.Message, Body: body}
+	}
+	if schema == nil {
+		return nil
+	}
+	return json.Unmarshal(e.Payload, schema)
+}
+
+type orderBookV3 struct {
+	Asks      []*orderBookEntryV3 `json:"asks"`
+	Bids      []*orderBookEntryV3 `json:"bids"`
+	UpdatedAt timestamp           `json:"updated_at"`
+	Sequence  int64               `json:"sequence,string"`
+}
+
+type orderBookEntryV3 struct {
+	Book   Book    `json:"book"`
+	Price  Decimal `json:"price"`
+	Amount Decimal `json:"amount"`
+	Oid    string  `json:"oid,omitempty"`
+}
+
+func (o *orderBookV3) orderBookInfo() *OrderBookInfo {
+	entries := func(e []*orderBookEntryV3) []*PriceLevel {
+		s := make([]*PriceLevel, len(e))
+		for i, entry := range e {
+			s[i] = &PriceLevel{Price: entry.Price, Amount: entry.Amount}
+		}
+		return s
+	}
+	return &OrderBookInfo{
+		Asks:      entries(o.Asks),
+		Bids:      entries(o.Bids),
+		UpdatedAt: o.UpdatedAt.Time(),
+		Sequence:  o.Sequence,
+	}
+}
+
+// fullOrderBookInfo converts an order book fetched without
+// aggregation, every entry must have an id.
+func (o *orderBookV3) fullOrderBookInfo() (*FullOrderBookInfo, error) {
+	entries := func(e []*orderBookEntryV3) ([]*OrderBookEntry, error) {
+		s := make([]*OrderBookEntry, len(e))
+		for i, entry := range e {
+			if entry.Oid == "" {
+				return nil, errNoOrderIds
+			}
+			s[i] = &OrderBookEntry{Id: entry.Oid, Price: entry.Price, Amount: entry.Amount}
+		}
+		return s, nil
+	}
+	asks, err := entries(o.Asks)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := entries(o.Bids)
+	if err != nil {
+		return nil, err
+	}
+	return &FullOrderBookInfo{
+		Asks:      asks,
+		Bids:      bids,
+		UpdatedAt: o.UpdatedAt.Time(),
+		Sequence:  o.Sequence,
+	}, nil
+}
+
+type tradeV3 struct {
+	Book      Book      `json:"book"`
+	CreatedAt timestamp `json:"created_at"`
+	Amount    Decimal   `json:"amount"`
+	MakerSide string    `json:"maker_side"`
+	Price     Decimal   `json:"price"`
+	Tid       int       `json:"tid"`
+}
+
+func (t *tradeV3) transaction() *Transaction {
+	return &Transaction{
+		Book:   t.Book,
+		Amount: t.Amount,
+		Date:   t.CreatedAt.Time(),
+		Price:  t.Price,
+		Tid:    t.Tid,
+		Side:   t.MakerSide,
+	}
+}
+
+type balanceV3 struct {
+	Balances []*CurrencyBalance `json:"balances"`
+}
+
+func (b *balanceV3) balance() *Balance {
+	balance := &Balance{Balances: b.Balances}
+	for _, cb := range b.Balances {
+		switch cb.Currency {
+		case MXN:
+			balance.MXNBalance = cb.Total
+			balance.MXNReserved = cb.Locked
+			balance.MXNAvailable = cb.Available
+		case BTC:
+			balance.BTCBalance = cb.Total
+			balance.BTCReserved = cb.Locked
+			balance.BTCAvailable = cb.Available
+		}
+	}
+	return balance
+}
+
+type orderV3 struct {
+	Book           Book      `json:"book"`
+	OriginalAmount Decimal   `json:"original_amount"`
+	UnfilledAmount Decimal   `json:"unfilled_amount"`
+	OriginalValue  Decimal   `json:"original_value"`
+	CreatedAt      timestamp `json:"created_at"`
+	UpdatedAt      timestamp `json:"updated_at"`
+	Price          Decimal   `json:"price"`
+	Oid            string    `json:"oid"`
+	Side           string    `json:"side"`
+	Status         string    `json:"status"`
+	Type           string    `json:"type"`
+}
+
+func (o *orderV3) order() *Order {
+	return &Order{
+		Id:             o.Oid,
+		Type:           o.Type,
+		Side:           o.Side,
+		Price:          o.Price,
+		Amount:         o.UnfilledAmount,
+		OriginalAmount: o.OriginalAmount,
+		UnfilledAmount: o.UnfilledAmount,
+		Datetime:       o.CreatedAt.Time(),
+		UpdatedAt:      o.UpdatedAt.Time(),
+		Status:         o.Status,
+		Book:           o.Book,
+	}
+}
+
+func ordersFromV3(o []*orderV3) []*Order {
+	orders := make([]*Order, len(o))
+	for i, order := range o {
+		orders[i] = order.order()
+	}
+	return orders
+}
+
+func (c *Client) orderBookV3(ctx context.Context, v *url.Values) (*OrderBookInfo, error) {
+	orderBook := &orderBookV3{}
+	if err := c.get(ctx, orderBookPathV3, v, orderBook); err != nil {
+		return nil, err
+	}
+	return orderBook.orderBookInfo(), nil
+}
+
+// orderBookOrdersV3 returns every order of book with its id.
+func (c *Client) orderBookOrdersV3(ctx context.Context, book Book) (*orderBookV3, error) {
+	v := &url.Values{}
+	v.Set("book", book.String())
+	v.Set("aggregate", "false")
+	orderBook := &orderBookV3{}
+	if err := c.get(ctx, orderBookPathV3, v, orderBook); err != nil {
+		return nil, err
+	}
+	return orderBook, nil
+}
+
+func (c *Client) tradesV3(ctx context.Context, v *url.Values) ([]*Transaction, error) {
+	var trades []*tradeV3
+	if err := c.get(ctx, tradesPathV3, v, &trades); err != nil {
+		return nil, err
+	}
+	transactions := make([]*Transaction, len(trades))
+	for i, trade := range trades {
+		transactions[i] = trade.transaction()
+	}
+	return transactions, nil
+}
+
+func (c *Account) balanceV3(ctx context.Context) (*Balance, error) {
+	balance := &balanceV3{}
+	if err := c.request(ctx, "GET", balancePathV3, nil, nil, balance); err != nil {
+		return nil, err
+	}
+	return balance.balance(), nil
+}
+
+func (c *Account) openOrdersV3(ctx context.Context, book Book) ([]*Order, error) {
+	var orders []*orderV3
+	var v *url.Values
+	if book != "" {
+		v = &url.Values{}
+		v.Set("book", book.String())
+	}
+	if err := c.request(ctx, "GET", openOrdersPathV3, v, nil, &orders); err != nil {
+		return nil, err
+	}
+	return ordersFromV3(orders), nil
+}
+
+func (c *Account) lookupOrderV3(ctx context.Context, id string) ([]*Order, error) {
+	var orders []*orderV3
+	path := idsPath(ordersPathV3, []string{id})
+	if err := c.request(ctx, "GET", path, nil, nil, &orders); err != nil {
+		return nil, err
+	}
+	return ordersFromV3(orders), nil
+}
+
+// idsPath appends the ids to path as a single
+// dash separated segment.
+func idsPath(path string, ids []string) string {
+	escaped := make([]string, len(ids))
+	for i, id := range ids {
+		escaped[i] = url.PathEscape(id)
+	}
+	return path + strings.Join(escaped, "-") + "/"
+}
+
+// request performs a v3 private call, signing method, path and payload
+// in the Authorization header.
+func (c *Account) request(ctx context.Context, method, path string, query *url.Values, payload []byte, schema interface{}) error {
+	u, err := url.Parse(c.client.baseURL() + path)
+	if err != nil {
+		return err
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+	requestPath := u.EscapedPath()
+	if u.RawQuery != "" {
+		requestPath += "?" + u.RawQuery
+	}
+	// Only the GET calls are read-only, every attempt
+	// is signed with a new nonce.
+	var body []byte
+	err = c.client.retry(ctx, method == "GET", func() error {
+		nonce, err := c.nonce()
+		if err != nil {
+			return err
+		}
+		signature, err := c.getSignatureV3(ctx, nonce, method, requestPath, payload)
+		if err != nil {
+			return err
+		}
+		header := http.Header{}
+		header.Set("Authorization", fmt.Sprintf("%s %s:%v:%s", authorizationScheme, c.keys.Key, nonce, signature))
+		body, err = c.client.do(ctx, c.client.PrivateLimit, method, u.String(), payload, header)
+		return err
+	})
+	if err != nil {
+		return withResponse(err, path, body)
+	}
+	return withResponse(decodeEnvelope(body, schema), path, body)
+}
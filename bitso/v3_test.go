@@ -0,0 +1,319 @@
+package bitso
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestV3(t *testing.T) {
+	httpmock.Activate()
+	registerResponderV3()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given a client using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+
+		Convey("The base URL should be URLv3", func() {
+			So(client.baseURL(), ShouldEqual, URLv3)
+		})
+
+		Convey("When the ticker is requested", func() {
+			ticker, err := client.Ticker(BTCMXN)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The payload should be decoded", func() {
+				So(ticker.High.String(), ShouldEqual, "12700.00")
+				So(ticker.Book, ShouldEqual, BTCMXN)
+				So(ticker.CreatedAt.Equal(time.Date(2016, 4, 8, 17, 52, 31, 0, time.UTC)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the grouped order book is requested", func() {
+			orderBook, err := client.OrderBook(BTCMXN, true)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The entries should be converted to price levels", func() {
+				So(orderBook.Bids, ShouldHaveLength, 2)
+				So(orderBook.Bids[0].Price.String(), ShouldEqual, "5632.24")
+				So(orderBook.Bids[0].Amount.String(), ShouldEqual, "1.34491802")
+				So(orderBook.Sequence, ShouldEqual, 27214)
+			})
+		})
+
+		Convey("When the ungrouped order book is requested", func() {
+			orderBook, err := client.OrderBook(BTCMXN, false)
+
+			Convey("Every order should have its own entry", func() {
+				So(err, ShouldBeNil)
+				So(orderBook.Bids, ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("When the full order book is requested", func() {
+			orderBook, err := client.FullOrderBook(BTCMXN)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Every order should have its id", func() {
+				So(orderBook.Bids, ShouldHaveLength, 3)
+				So(orderBook.Bids[1].Id, ShouldEqual, "b2")
+				So(orderBook.Bids[1].Amount.String(), ShouldEqual, "0.34491802")
+				So(orderBook.Asks[0].Id, ShouldEqual, "a1")
+				So(orderBook.Sequence, ShouldEqual, 27214)
+			})
+		})
+
+		Convey("When the full order book of a book without ids is requested", func() {
+			_, err := client.FullOrderBook(ETHMXN)
+
+			Convey("err should be errNoOrderIds", func() {
+				So(err, ShouldEqual, errNoOrderIds)
+			})
+		})
+
+		Convey("When the trades are requested", func() {
+			transactions, err := client.Transactions(BTCMXN, "")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The maker side should be the side", func() {
+				So(transactions, ShouldHaveLength, 1)
+				So(transactions[0].Side, ShouldEqual, "buy")
+				So(transactions[0].Tid, ShouldEqual, 51756)
+			})
+		})
+
+		Convey("And an account with valid keys", func() {
+			account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+			Convey("When the balance is requested", func() {
+				balance, err := account.Balance()
+
+				Convey("err should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("Every currency should be listed", func() {
+					So(balance.Balances, ShouldHaveLength, 2)
+				})
+
+				Convey("The MXN fields should be filled", func() {
+					So(balance.MXNAvailable.String(), ShouldEqual, "26864.57")
+				})
+			})
+
+			Convey("When the open orders are requested", func() {
+				orders, err := account.OpenOrders()
+
+				Convey("err should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("The oid should be the id", func() {
+					So(orders, ShouldHaveLength, 1)
+					So(orders[0].Id, ShouldEqual, "543cr2v32a1h6844")
+					So(orders[0].Side, ShouldEqual, "sell")
+				})
+
+				Convey("The dates should be decoded", func() {
+					So(orders[0].UpdatedAt.Sub(orders[0].Datetime), ShouldEqual, 20*time.Second)
+				})
+			})
+
+			Convey("When an order is looked up", func() {
+				orders, err := account.LookupOrder("543cr2v32a1h6844")
+
+				Convey("err should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("The order should be returned", func() {
+					So(orders, ShouldHaveLength, 1)
+				})
+			})
+		})
+
+		Convey("And an account with invalid keys", func() {
+			account := client.Authenticate(&Keys{Key: "invalid", Secret: "secret"})
+
+			Convey("When the balance is requested", func() {
+				_, err := account.Balance()
+
+				Convey("The envelope error should be returned", func() {
+					var apiErr *APIError
+					So(errors.As(err, &apiErr), ShouldBeTrue)
+					So(apiErr.Code, ShouldEqual, "0201")
+					So(apiErr.Endpoint, ShouldEqual, balancePathV3)
+					So(string(apiErr.Body), ShouldContainSubstring, "Invalid Nonce")
+				})
+
+				Convey("It should match the signature and nonce sentinels", func() {
+					So(errors.Is(err, ErrInvalidSignature), ShouldBeTrue)
+					So(errors.Is(err, ErrInvalidNonce), ShouldBeTrue)
+					So(errors.Is(err, ErrInsufficientFunds), ShouldBeFalse)
+				})
+			})
+		})
+	})
+}
+
+// authorizeV3 verifies the Authorization header of a v3 private request.
+func authorizeV3(req *http.Request) bool {
+	auth := strings.TrimPrefix(req.Header.Get("Authorization"), "Bitso ")
+	parts := strings.Split(auth, ":")
+	if len(parts) != 3 || parts[0] != "key" {
+		return false
+	}
+	requestPath := req.URL.EscapedPath()
+	if req.URL.RawQuery != "" {
+		requestPath += "?" + req.URL.RawQuery
+	}
+	var body []byte
+	if req.Body != nil {
+		body, _ = ioutil.ReadAll(req.Body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	message := fmt.Sprintf("%s%s%s%s", parts[1], req.Method, requestPath, body)
+	return sign(message, "secret") == parts[2]
+}
+
+func v3Response(payload string) (*http.Response, error) {
+	return httpmock.NewStringResponse(200, `{"success": true, "payload": `+payload+`}`), nil
+}
+
+func v3Unauthorized() (*http.Response, error) {
+	return httpmock.NewStringResponse(401, `{"success": false, "error": {"code": "0201", "message": "Invalid Nonce or Invalid Signature"}}`), nil
+}
+
+func registerResponderV3() {
+	registerBooksResponder()
+
+	httpmock.RegisterResponder("GET", URLv3+tickerPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			return v3Response(`{
+				"book": "btc_mxn",
+				"volume": "22.31349615",
+				"high": "12700.00",
+				"last": "12640.00",
+				"low": "12388.17",
+				"vwap": "12505.15042596",
+				"ask": "12640.00",
+				"bid": "12554.88",
+				"created_at": "2016-04-08T17:52:31.000+00:00"
+			}`)
+		},
+	)
+
+	httpmock.RegisterResponder("GET", URLv3+orderBookPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			v := req.URL.Query()
+			switch {
+			case v.Get("aggregate") == "true":
+				return v3Response(`{
+					"asks": [{"book": "btc_mxn", "price": "5632.24", "amount": "1.34491802"}],
+					"bids": [
+						{"book": "btc_mxn", "price": "5632.24", "amount": "1.34491802"},
+						{"book": "btc_mxn", "price": "5631.44", "amount": "0.50000000"}
+					],
+					"updated_at": "2016-04-08T17:52:31.000+00:00",
+					"sequence": "27214"
+				}`)
+			case v.Get("aggregate") != "false":
+				return httpmock.NewStringResponse(400, `{"success": false, "error": {"code": "0301", "message": "Invalid aggregate value"}}`), nil
+			case v.Get("book") == ETHMXN.String():
+				return v3Response(`{
+					"asks": [{"book": "eth_mxn", "price": "3010.00", "amount": "1.5"}],
+					"bids": [{"book": "eth_mxn", "price": "3000.00", "amount": "2"}],
+					"sequence": "513"
+				}`)
+			}
+			return v3Response(`{
+				"asks": [{"book": "btc_mxn", "price": "5632.24", "amount": "1.34491802", "oid": "a1"}],
+				"bids": [
+					{"book": "btc_mxn", "price": "5632.24", "amount": "1.00000000", "oid": "b1"},
+					{"book": "btc_mxn", "price": "5632.24", "amount": "0.34491802", "oid": "b2"},
+					{"book": "btc_mxn", "price": "5631.44", "amount": "0.50000000", "oid": "b3"}
+				],
+				"updated_at": "2016-04-08T17:52:31.000+00:00",
+				"sequence": "27214"
+			}`)
+		},
+	)
+
+	httpmock.RegisterResponder("GET", URLv3+tradesPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			return v3Response(`[{
+				"book": "btc_mxn",
+				"created_at": "2016-04-08T17:52:31.000+00:00",
+				"amount": "0.02000000",
+				"maker_side": "buy",
+				"price": "5545.01",
+				"tid": 51756
+			}]`)
+		},
+	)
+
+	httpmock.RegisterResponder("GET", URLv3+balancePathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response(`{"balances": [
+				{"currency": "mxn", "total": "26864.57", "locked": "0.00", "available": "26864.57"},
+				{"currency": "btc", "total": "46.67902107", "locked": "0.00000000", "available": "46.67902107"}
+			]}`)
+		},
+	)
+
+	order := `{
+		"book": "btc_mxn",
+		"original_amount": "0.01000000",
+		"unfilled_amount": "0.00500000",
+		"original_value": "56.0",
+		"created_at": "2016-04-08T17:52:31.000+00:00",
+		"updated_at": "2016-04-08T17:52:51.000+00:00",
+		"price": "5600.00",
+		"oid": "543cr2v32a1h6844",
+		"side": "sell",
+		"status": "partial-fill",
+		"type": "limit"
+	}`
+
+	httpmock.RegisterResponder("GET", URLv3+openOrdersPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response("[" + order + "]")
+		},
+	)
+
+	httpmock.RegisterResponder("GET", URLv3+ordersPathV3+"543cr2v32a1h6844/",
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			return v3Response("[" + order + "]")
+		},
+	)
+}
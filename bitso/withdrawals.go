@@ -0,0 +1,206 @@
+package bitso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	bitcoinWithdrawalPathV3 = "bitcoin_withdrawal/"
+	etherWithdrawalPathV3   = "ether_withdrawal/"
+	speiWithdrawalPathV3    = "spei_withdrawal/"
+	withdrawalsPathV3       = "withdrawals/"
+	// maxNotesRef and maxNumericRef are the limits of the
+	// SPEI references.
+	maxNotesRef   = 40
+	maxNumericRef = 7
+)
+
+var (
+	errInvalidAmount     = errors.New("Invalid amount value")
+	errMissingRecipient  = errors.New("The recipient names are required")
+	errInvalidNotesRef   = errors.New("Invalid notes reference, it must have up to 40 characters")
+	errInvalidNumericRef = errors.New("Invalid numeric reference, it must have up to 7 digits")
+)
+
+// Withdrawal is a transfer of funds out of the account.
+type Withdrawal struct {
+	Id        string             `json:"wid"`
+	Status    string             `json:"status"`
+	CreatedAt time.Time          `json:"created_at"`
+	Currency  Currency           `json:"currency"`
+	Method    string             `json:"method"`
+	Amount    Decimal            `json:"amount"`
+	Details   *WithdrawalDetails `json:"details"`
+}
+
+// UnmarshalJSON decodes the dates in any of the formats of the API.
+func (w *Withdrawal) UnmarshalJSON(data []byte) error {
+	type withdrawal Withdrawal
+	v := &struct {
+		*withdrawal
+		CreatedAt timestamp `json:"created_at"`
+	}{withdrawal: (*withdrawal)(w)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	w.CreatedAt = v.CreatedAt.Time()
+	return nil
+}
+
+// WithdrawalDetails holds the destination of a withdrawal,
+// only the fields matching its method are set.
+type WithdrawalDetails struct {
+	Address          string `json:"withdrawal_address,omitempty"`
+	TxHash           string `json:"tx_hash,omitempty"`
+	BeneficiaryName  string `json:"beneficiary_name,omitempty"`
+	BeneficiaryCLABE string `json:"beneficiary_clabe,omitempty"`
+	BeneficiaryBank  string `json:"beneficiary_bank,omitempty"`
+	NumericRef       string `json:"numeric_reference,omitempty"`
+	NotesRef         string `json:"concepto,omitempty"`
+	TrackingKey      string `json:"clave_rastreo,omitempty"`
+}
+
+type cryptoWithdrawal struct {
+	Amount  Decimal `json:"amount"`
+	Address string  `json:"address"`
+}
+
+// SPEIWithdrawal describes a transfer of pesos to a bank account.
+// NotesRef and NumericRef are optional.
+type SPEIWithdrawal struct {
+	RecipientGivenNames  string  `json:"recipient_given_names"`
+	RecipientFamilyNames string  `json:"recipient_family_names"`
+	CLABE                string  `json:"clabe"`
+	Amount               Decimal `json:"amount"`
+	NotesRef             string  `json:"notes_ref,omitempty"`
+	NumericRef           string  `json:"numeric_ref,omitempty"`
+}
+
+func (w *SPEIWithdrawal) validate() error {
+	if w.RecipientGivenNames == "" || w.RecipientFamilyNames == "" {
+		return errMissingRecipient
+	}
+	if err := ValidateCLABE(w.CLABE); err != nil {
+		return err
+	}
+	if err := validateAmount(w.Amount); err != nil {
+		return err
+	}
+	if len([]rune(w.NotesRef)) > maxNotesRef {
+		return errInvalidNotesRef
+	}
+	if len(w.NumericRef) > maxNumericRef {
+		return errInvalidNumericRef
+	}
+	for _, r := range w.NumericRef {
+		if r < '0' || r > '9' {
+			return errInvalidNumericRef
+		}
+	}
+	return nil
+}
+
+// WithdrawSPEI sends pesos to the bank account identified by
+// w.CLABE. The request is validated before it's signed.
+func (c *Account) WithdrawSPEI(w *SPEIWithdrawal) (*Withdrawal, error) {
+	return c.WithdrawSPEIContext(context.Background(), w)
+}
+
+// WithdrawSPEIContext is like WithdrawSPEI with a context.
+func (c *Account) WithdrawSPEIContext(ctx context.Context, w *SPEIWithdrawal) (*Withdrawal, error) {
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return c.withdraw(ctx, speiWithdrawalPathV3, payload)
+}
+
+// WithdrawBTC sends amount bitcoins to address. The address is
+// validated before the request is signed.
+func (c *Account) WithdrawBTC(amount Decimal, address string) (*Withdrawal, error) {
+	return c.WithdrawBTCContext(context.Background(), amount, address)
+}
+
+// WithdrawBTCContext is like WithdrawBTC with a context.
+func (c *Account) WithdrawBTCContext(ctx context.Context, amount Decimal, address string) (*Withdrawal, error) {
+	if err := ValidateBTCAddress(address); err != nil {
+		return nil, err
+	}
+	return c.withdrawCrypto(ctx, bitcoinWithdrawalPathV3, amount, address)
+}
+
+// WithdrawETH sends amount ethers to address. The address is
+// validated before the request is signed.
+func (c *Account) WithdrawETH(amount Decimal, address string) (*Withdrawal, error) {
+	return c.WithdrawETHContext(context.Background(), amount, address)
+}
+
+// WithdrawETHContext is like WithdrawETH with a context.
+func (c *Account) WithdrawETHContext(ctx context.Context, amount Decimal, address string) (*Withdrawal, error) {
+	if err := ValidateETHAddress(address); err != nil {
+		return nil, err
+	}
+	return c.withdrawCrypto(ctx, etherWithdrawalPathV3, amount, address)
+}
+
+func (c *Account) withdrawCrypto(ctx context.Context, path string, amount Decimal, address string) (*Withdrawal, error) {
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(&cryptoWithdrawal{Amount: amount, Address: address})
+	if err != nil {
+		return nil, err
+	}
+	return c.withdraw(ctx, path, payload)
+}
+
+// withdraw requests a withdrawal, they are only
+// available through the v3 API.
+func (c *Account) withdraw(ctx context.Context, path string, payload []byte) (*Withdrawal, error) {
+	if c.client.Version != V3 {
+		return nil, errV3Only
+	}
+	withdrawal := &Withdrawal{}
+	if err := c.request(ctx, "POST", path, nil, payload, withdrawal); err != nil {
+		return nil, err
+	}
+	return withdrawal, nil
+}
+
+/*
+Withdrawals returns the withdrawals of the account paginated by
+page, page may be nil.
+
+When ids are given only those withdrawals are returned and page
+is ignored. It's only available through the v3 API.
+*/
+func (c *Account) Withdrawals(page *Page, ids ...string) ([]*Withdrawal, error) {
+	return c.WithdrawalsContext(context.Background(), page, ids...)
+}
+
+// WithdrawalsContext is like Withdrawals with a context.
+func (c *Account) WithdrawalsContext(ctx context.Context, page *Page, ids ...string) ([]*Withdrawal, error) {
+	if c.client.Version != V3 {
+		return nil, errV3Only
+	}
+	var withdrawals []*Withdrawal
+	path, v := historyQuery(withdrawalsPathV3, page, ids)
+	if err := c.request(ctx, "GET", path, v, nil, &withdrawals); err != nil {
+		return nil, err
+	}
+	return withdrawals, nil
+}
+
+// validateAmount checks that amount is a positive number.
+func validateAmount(amount Decimal) error {
+	if amount.Sign() <= 0 {
+		return errInvalidAmount
+	}
+	return nil
+}
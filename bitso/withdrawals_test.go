@@ -0,0 +1,239 @@
+package bitso
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithdrawals(t *testing.T) {
+	httpmock.Activate()
+	registerWithdrawalsResponder()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Given an account using the v3 API", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When bitcoins are withdrawn to a valid address", func() {
+			withdrawal, err := account.WithdrawBTC(MustParseDecimal("0.5"), "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The withdrawal id and status should be returned", func() {
+				So(withdrawal.Id, ShouldEqual, "c5b8d7f0768ee91d3b33bee648318688")
+				So(withdrawal.Status, ShouldEqual, "pending")
+				So(withdrawal.Details.Address, ShouldEqual, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+			})
+		})
+
+		Convey("When bitcoins are withdrawn to an invalid address", func() {
+			_, err := account.WithdrawBTC(MustParseDecimal("0.5"), "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3")
+
+			Convey("The request should not be sent", func() {
+				So(err, ShouldEqual, errAddressChecksum)
+			})
+		})
+
+		Convey("When ethers are withdrawn to a valid address", func() {
+			withdrawal, err := account.WithdrawETH(MustParseDecimal("1.25"), "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The currency should be eth", func() {
+				So(withdrawal.Currency, ShouldEqual, ETH)
+			})
+		})
+
+		Convey("When a negative amount is withdrawn", func() {
+			_, err := account.WithdrawETH(MustParseDecimal("-1"), "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+			Convey("err should be errInvalidAmount", func() {
+				So(err, ShouldEqual, errInvalidAmount)
+			})
+		})
+	})
+
+	Convey("Given a SPEI withdrawal", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+		w := &SPEIWithdrawal{
+			RecipientGivenNames:  "Juan",
+			RecipientFamilyNames: "Pérez López",
+			CLABE:                "002010077777777771",
+			Amount:               MustParseDecimal("1500.00"),
+			NotesRef:             "Pago de nómina",
+			NumericRef:           "1234567",
+		}
+
+		Convey("When it's sent", func() {
+			withdrawal, err := account.WithdrawSPEI(w)
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The withdrawal record should be typed", func() {
+				So(withdrawal.Method, ShouldEqual, "sp")
+				So(withdrawal.Details.BeneficiaryName, ShouldEqual, "Juan Pérez López")
+				So(withdrawal.Details.BeneficiaryCLABE, ShouldEqual, "002010077777777771")
+				So(withdrawal.Details.NotesRef, ShouldEqual, "Pago de nómina")
+			})
+		})
+
+		Convey("When the CLABE has a wrong control digit", func() {
+			w.CLABE = "002010077777777772"
+			_, err := account.WithdrawSPEI(w)
+
+			Convey("err should be errCLABEChecksum", func() {
+				So(err, ShouldEqual, errCLABEChecksum)
+			})
+		})
+
+		Convey("When the numeric reference is too long", func() {
+			w.NumericRef = "12345678"
+			_, err := account.WithdrawSPEI(w)
+
+			Convey("err should be errInvalidNumericRef", func() {
+				So(err, ShouldEqual, errInvalidNumericRef)
+			})
+		})
+
+		Convey("When the recipient is missing", func() {
+			w.RecipientFamilyNames = ""
+			_, err := account.WithdrawSPEI(w)
+
+			Convey("err should be errMissingRecipient", func() {
+				So(err, ShouldEqual, errMissingRecipient)
+			})
+		})
+	})
+
+	Convey("Given an account with withdrawals", t, func() {
+		client := NewClient()
+		client.Version = V3
+		account := client.Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When the withdrawals are requested", func() {
+			withdrawals, err := account.Withdrawals(&Page{Marker: "a", Limit: 10})
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The withdrawals after the marker should be returned", func() {
+				So(withdrawals, ShouldHaveLength, 2)
+				So(withdrawals[1].Id, ShouldEqual, "c")
+			})
+		})
+
+		Convey("When withdrawals are requested by id", func() {
+			withdrawals, err := account.Withdrawals(nil, "a", "c")
+
+			Convey("err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Only those withdrawals should be returned", func() {
+				So(withdrawals, ShouldHaveLength, 2)
+				So(withdrawals[0].Id, ShouldEqual, "a")
+			})
+		})
+	})
+
+	Convey("Given an account using the v2 API", t, func() {
+		account := Authenticate(&Keys{Key: "key", Secret: "secret"})
+
+		Convey("When bitcoins are withdrawn", func() {
+			_, err := account.WithdrawBTC(MustParseDecimal("0.5"), "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+
+			Convey("err should be errV3Only", func() {
+				So(err, ShouldEqual, errV3Only)
+			})
+		})
+	})
+}
+
+func registerWithdrawalsResponder() {
+	withdrawal := func(currency Currency, method string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			w := &cryptoWithdrawal{}
+			if err := json.NewDecoder(req.Body).Decode(w); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"success": true,
+				"payload": &Withdrawal{
+					Id:        "c5b8d7f0768ee91d3b33bee648318688",
+					Status:    "pending",
+					CreatedAt: time.Date(2016, 4, 8, 17, 52, 31, 0, time.UTC),
+					Currency:  currency,
+					Method:    method,
+					Amount:    w.Amount,
+					Details:   &WithdrawalDetails{Address: w.Address},
+				},
+			})
+		}
+	}
+	history := func(ids ...string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			withdrawals := make([]*Withdrawal, len(ids))
+			for i, id := range ids {
+				withdrawals[i] = &Withdrawal{Id: id, Status: "complete", Currency: "btc", Method: "Bitcoin"}
+			}
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"success": true,
+				"payload": withdrawals,
+			})
+		}
+	}
+	httpmock.RegisterResponder("GET", URLv3+withdrawalsPathV3+"?limit=10&marker=a", history("b", "c"))
+	httpmock.RegisterResponder("GET", URLv3+withdrawalsPathV3+"a-c/", history("a", "c"))
+
+	httpmock.RegisterResponder("POST", URLv3+speiWithdrawalPathV3,
+		func(req *http.Request) (*http.Response, error) {
+			if !authorizeV3(req) {
+				return v3Unauthorized()
+			}
+			w := &SPEIWithdrawal{}
+			if err := json.NewDecoder(req.Body).Decode(w); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"success": true,
+				"payload": &Withdrawal{
+					Id:        "p4u8d7f0768ee91d3b33bee6483132i8",
+					Status:    "pending",
+					CreatedAt: time.Date(2016, 4, 8, 17, 52, 31, 0, time.UTC),
+					Currency:  "mxn",
+					Method:    "sp",
+					Amount:    w.Amount,
+					Details: &WithdrawalDetails{
+						BeneficiaryName:  w.RecipientGivenNames + " " + w.RecipientFamilyNames,
+						BeneficiaryCLABE: w.CLABE,
+						NumericRef:       w.NumericRef,
+						NotesRef:         w.NotesRef,
+					},
+				},
+			})
+		},
+	)
+	httpmock.RegisterResponder("POST", URLv3+bitcoinWithdrawalPathV3, withdrawal(BTC, "Bitcoin"))
+	httpmock.RegisterResponder("POST", URLv3+etherWithdrawalPathV3, withdrawal(ETH, "Ether"))
+}
@@ -0,0 +1,152 @@
+/*
+Package credentials resolves the Keys of the Bitso API.
+
+Keys are looked up in order of precedence:
+
+ 1. The BITSO_KEY, BITSO_SECRET and BITSO_CLIENT_ID environment
+    variables, when BITSO_KEY is set.
+ 2. The profile in the vault file, when a passphrase is given.
+ 3. The profile in the config file.
+
+The profile is the one given in the Options, else the one in the
+BITSO_PROFILE environment variable, else "default".
+*/
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dsmontoya/gobitso/bitso"
+)
+
+// Environment variables read by Resolve.
+const (
+	EnvKey        = "BITSO_KEY"
+	EnvSecret     = "BITSO_SECRET"
+	EnvClientId   = "BITSO_CLIENT_ID"
+	EnvProfile    = "BITSO_PROFILE"
+	EnvPassphrase = "BITSO_VAULT_PASSPHRASE"
+)
+
+// DefaultProfile is the profile used when none is given.
+const DefaultProfile = "default"
+
+// ErrNotFound is returned when no source has the keys.
+var ErrNotFound = errors.New("Credentials not found")
+
+// Profile holds the keys of an account.
+type Profile struct {
+	Key      string `json:"key"`
+	Secret   string `json:"secret"`
+	ClientId string `json:"client_id"`
+}
+
+// Keys returns the keys of p.
+func (p *Profile) Keys() *bitso.Keys {
+	return &bitso.Keys{Key: p.Key, Secret: p.Secret, ClientId: p.ClientId}
+}
+
+// Profiles are the named profiles of a config or vault file,
+// a JSON object with a Profile for every name.
+type Profiles map[string]*Profile
+
+// Options tell where the keys are looked up. Their zero
+// value uses the defaults of every field.
+type Options struct {
+	// Profile is the name of the profile.
+	// Leaving it blank uses BITSO_PROFILE or DefaultProfile.
+	Profile string
+	// ConfigFile is the path of the config file.
+	// Leaving it blank uses DefaultConfigFile.
+	ConfigFile string
+	// VaultFile is the path of the vault file.
+	// Leaving it blank uses DefaultVaultFile.
+	VaultFile string
+	// Passphrase opens the vault file.
+	// Leaving it blank uses BITSO_VAULT_PASSPHRASE.
+	Passphrase string
+}
+
+// DefaultConfigFile returns the path of the default
+// config file, ~/.bitso/credentials.json.
+func DefaultConfigFile() string {
+	return defaultFile("credentials.json")
+}
+
+// DefaultVaultFile returns the path of the default
+// vault file, ~/.bitso/vault.json.
+func DefaultVaultFile() string {
+	return defaultFile("vault.json")
+}
+
+func defaultFile(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".bitso", name)
+}
+
+/*
+Resolve returns the keys of the first source holding them, opts may
+be nil.
+
+A vault that can't be opened with the passphrase is an error, it's
+never skipped. ErrNotFound is returned when no source has the keys.
+*/
+func Resolve(opts *Options) (*bitso.Keys, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if key := os.Getenv(EnvKey); key != "" {
+		return &bitso.Keys{
+			Key:      key,
+			Secret:   os.Getenv(EnvSecret),
+			ClientId: os.Getenv(EnvClientId),
+		}, nil
+	}
+	name := firstOf(opts.Profile, os.Getenv(EnvProfile), DefaultProfile)
+	if passphrase := firstOf(opts.Passphrase, os.Getenv(EnvPassphrase)); passphrase != "" {
+		profiles, err := ReadVault(firstOf(opts.VaultFile, DefaultVaultFile()), passphrase)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if p, ok := profiles[name]; ok {
+			return p.Keys(), nil
+		}
+	}
+	profiles, err := ReadConfig(firstOf(opts.ConfigFile, DefaultConfigFile()))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if p, ok := profiles[name]; ok {
+		return p.Keys(), nil
+	}
+	return nil, ErrNotFound
+}
+
+// ReadConfig reads the profiles of the config file in path.
+func ReadConfig(path string) (Profiles, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := Profiles{}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package credentials
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// setenv sets the environment variables until the end of the Convey.
+func setenv(vars map[string]string) {
+	for k, v := range vars {
+		previous, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		k := k
+		Reset(func() {
+			if ok {
+				os.Setenv(k, previous)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	Convey("Given a config file and a vault with several profiles", t, func() {
+		dir, err := ioutil.TempDir("", "credentials")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		setenv(map[string]string{EnvKey: "", EnvProfile: "", EnvPassphrase: ""})
+		config := filepath.Join(dir, "credentials.json")
+		So(ioutil.WriteFile(config, []byte(`{
+			"default": {"key": "config", "secret": "configSecret", "client_id": "1"},
+			"staging": {"key": "configStaging", "secret": "stagingSecret", "client_id": "2"}
+		}`), 0600), ShouldBeNil)
+		vaultFile := filepath.Join(dir, "vault.json")
+		So(WriteVault(vaultFile, "passphrase", Profiles{
+			"default": {Key: "vault", Secret: "vaultSecret", ClientId: "3"},
+		}), ShouldBeNil)
+		opts := &Options{ConfigFile: config, VaultFile: vaultFile}
+
+		Convey("When the keys are resolved without a passphrase", func() {
+			keys, err := Resolve(opts)
+
+			Convey("They should come from the config file", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "config")
+				So(keys.Secret, ShouldEqual, "configSecret")
+				So(keys.ClientId, ShouldEqual, "1")
+			})
+		})
+
+		Convey("When the keys are resolved with the passphrase", func() {
+			opts.Passphrase = "passphrase"
+			keys, err := Resolve(opts)
+
+			Convey("The vault should take precedence over the config file", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "vault")
+				So(keys.Secret, ShouldEqual, "vaultSecret")
+			})
+		})
+
+		Convey("When the passphrase is in the environment", func() {
+			setenv(map[string]string{EnvPassphrase: "passphrase"})
+			keys, err := Resolve(opts)
+
+			Convey("The vault should be opened with it", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "vault")
+			})
+		})
+
+		Convey("When a profile missing from the vault is resolved", func() {
+			opts.Passphrase = "passphrase"
+			opts.Profile = "staging"
+			keys, err := Resolve(opts)
+
+			Convey("It should come from the config file", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "configStaging")
+			})
+		})
+
+		Convey("When the profile is in the environment", func() {
+			setenv(map[string]string{EnvProfile: "staging"})
+			keys, err := Resolve(opts)
+
+			Convey("It should be used", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "configStaging")
+			})
+		})
+
+		Convey("When the keys are in the environment", func() {
+			setenv(map[string]string{EnvKey: "env", EnvSecret: "envSecret", EnvClientId: "4"})
+			opts.Passphrase = "passphrase"
+			keys, err := Resolve(opts)
+
+			Convey("They should take precedence over the files", func() {
+				So(err, ShouldBeNil)
+				So(keys.Key, ShouldEqual, "env")
+				So(keys.Secret, ShouldEqual, "envSecret")
+				So(keys.ClientId, ShouldEqual, "4")
+			})
+		})
+
+		Convey("When the passphrase is wrong", func() {
+			opts.Passphrase = "wrong"
+			_, err := Resolve(opts)
+
+			Convey("err should be ErrWrongPassphrase", func() {
+				So(err, ShouldEqual, ErrWrongPassphrase)
+			})
+		})
+
+		Convey("When the profile doesn't exist", func() {
+			opts.Profile = "production"
+			_, err := Resolve(opts)
+
+			Convey("err should be ErrNotFound", func() {
+				So(err, ShouldEqual, ErrNotFound)
+			})
+		})
+
+		Convey("When there are no files", func() {
+			_, err := Resolve(&Options{
+				ConfigFile: filepath.Join(dir, "missing.json"),
+				VaultFile:  filepath.Join(dir, "missing_vault.json"),
+				Passphrase: "passphrase",
+			})
+
+			Convey("err should be ErrNotFound", func() {
+				So(err, ShouldEqual, ErrNotFound)
+			})
+		})
+	})
+}
@@ -0,0 +1,142 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Parameters of the scrypt key derivation of new vaults, the ones
+// recommended for interactive logins. They are stored in every vault.
+const (
+	scryptR   = 8
+	scryptP   = 1
+	saltBytes = 16
+	keyBytes  = 32
+)
+
+// Limits of the scrypt parameters read from a vault, a damaged one
+// could otherwise make the derivation use terabytes of memory. The
+// memory, 128*N*r bytes, is bounded to 1GiB, 32 times the one of
+// new vaults.
+const (
+	maxScryptN      = 1 << 20
+	maxScryptR      = 32
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30
+)
+
+// scryptN is a variable so the tests can derive keys faster.
+var scryptN = 1 << 15
+
+var errScryptParameters = errors.New("Invalid key derivation parameters in vault")
+
+// ErrWrongPassphrase is returned by the vaults that can't be decrypted.
+var ErrWrongPassphrase = errors.New("Wrong passphrase or corrupted vault")
+
+// vault is the content of a vault file. The profiles are encrypted
+// with AES-256-GCM by a key derived from the passphrase with scrypt.
+type vault struct {
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (v *vault) aead(passphrase string) (cipher.AEAD, error) {
+	if v.N < 2 || v.N > maxScryptN || v.R < 1 || v.R > maxScryptR ||
+		v.P < 1 || v.P > maxScryptP || 128*int64(v.N)*int64(v.R) > maxScryptMemory {
+		return nil, errScryptParameters
+	}
+	key, err := scrypt.Key([]byte(passphrase), v.Salt, v.N, v.R, v.P, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WriteVault encrypts profiles with passphrase into the vault file
+// in path, replacing it through a rename of a synced copy so a crash
+// never destroys it. The file is only readable by its owner.
+func WriteVault(path, passphrase string, profiles Profiles) error {
+	plaintext, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	v := &vault{N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, saltBytes)}
+	if _, err := rand.Read(v.Salt); err != nil {
+		return err
+	}
+	aead, err := v.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	v.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(v.Nonce); err != nil {
+		return err
+	}
+	v.Ciphertext = aead.Seal(nil, v.Nonce, plaintext, nil)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// TempFile creates the copy only readable by its owner.
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadVault decrypts the profiles of the vault file in path with
+// passphrase, ErrWrongPassphrase is returned when it can't.
+func ReadVault(path, passphrase string) (Profiles, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	v := &vault{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	aead, err := v.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(v.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := aead.Open(nil, v.Nonce, v.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	profiles := Profiles{}
+	if err := json.Unmarshal(plaintext, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
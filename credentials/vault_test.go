@@ -0,0 +1,97 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func init() {
+	scryptN = 1 << 10
+}
+
+func TestVault(t *testing.T) {
+	Convey("Given a vault written with a passphrase", t, func() {
+		dir, err := ioutil.TempDir("", "vault")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "vault.json")
+		profiles := Profiles{"default": {Key: "key", Secret: "secret", ClientId: "clientId"}}
+		So(WriteVault(path, "passphrase", profiles), ShouldBeNil)
+
+		Convey("The secret should not be stored in clear", func() {
+			data, err := ioutil.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldNotContainSubstring, "secret")
+		})
+
+		Convey("The file should only be readable by its owner", func() {
+			info, err := os.Stat(path)
+			So(err, ShouldBeNil)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+		})
+
+		Convey("When it's read with the passphrase", func() {
+			read, err := ReadVault(path, "passphrase")
+
+			Convey("The profiles should be decrypted", func() {
+				So(err, ShouldBeNil)
+				So(read, ShouldResemble, profiles)
+			})
+		})
+
+		Convey("When it's read with another passphrase", func() {
+			_, err := ReadVault(path, "wrong")
+
+			Convey("err should be ErrWrongPassphrase", func() {
+				So(err, ShouldEqual, ErrWrongPassphrase)
+			})
+		})
+
+		Convey("When it's written again with the same passphrase", func() {
+			data, _ := ioutil.ReadFile(path)
+			So(WriteVault(path, "passphrase", profiles), ShouldBeNil)
+			rewritten, _ := ioutil.ReadFile(path)
+
+			Convey("A new salt and nonce should be used", func() {
+				So(string(rewritten), ShouldNotEqual, string(data))
+			})
+		})
+
+		Convey("When it replaces a file readable by others", func() {
+			So(os.Chmod(path, 0644), ShouldBeNil)
+			So(WriteVault(path, "passphrase", profiles), ShouldBeNil)
+
+			Convey("It should only be readable by its owner", func() {
+				info, err := os.Stat(path)
+				So(err, ShouldBeNil)
+				So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+			})
+
+			Convey("No temporary file should be left behind", func() {
+				files, err := ioutil.ReadDir(dir)
+				So(err, ShouldBeNil)
+				So(files, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When its key derivation parameters are too large", func() {
+			v := &vault{}
+			data, _ := ioutil.ReadFile(path)
+			So(json.Unmarshal(data, v), ShouldBeNil)
+			for _, p := range [][3]int{{1 << 30, 8, 1}, {1 << 15, 1 << 20, 1}, {1 << 15, 8, 1 << 20}, {1 << 20, 32, 1}, {1 << 15, -1, 1}} {
+				v.N, v.R, v.P = p[0], p[1], p[2]
+				data, _ = json.Marshal(v)
+				So(ioutil.WriteFile(path, data, 0600), ShouldBeNil)
+				_, err := ReadVault(path, "passphrase")
+				So(err, ShouldEqual, errScryptParameters)
+			}
+		})
+	})
+}
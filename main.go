@@ -2,17 +2,16 @@ package main
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/dsmontoya/gobitso/bitso"
+	"github.com/dsmontoya/gobitso/credentials"
 )
 
 func main() {
 	//CLI
-	keys := &bitso.Keys{
-		Key:      os.Getenv("BITSO_KEY"),
-		Secret:   os.Getenv("BITSO_SECRET"),
-		ClientId: os.Getenv("BITSO_CLIENT_ID"),
+	keys, err := credentials.Resolve(nil)
+	if err != nil {
+		panic(err)
 	}
 	account := bitso.Authenticate(keys)
 	ticker, err := bitso.Ticker(bitso.BTCMXN)
@@ -35,6 +34,11 @@ func main() {
 		fmt.Println("err", err)
 	}
 	fmt.Println("balance", balance)
+	address, err := account.DepositAddressBTC()
+	if err != nil {
+		fmt.Println("err", err)
+	}
+	fmt.Println("deposit address", address)
 	orders, err := account.OpenOrders()
 	if err != nil {
 		panic(err)